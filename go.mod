@@ -1,6 +1,6 @@
 module github.com/slonegd-go/reversi
 
-go 1.14
+go 1.18
 
 require (
 	github.com/fatih/color v1.10.0
@@ -8,3 +8,11 @@ require (
 	github.com/stretchr/testify v1.1.4
 	golang.org/x/tools v0.0.0-20210104081019-d8d6ddbec6ee
 )
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/mattn/go-colorable v0.1.8 // indirect
+	github.com/mattn/go-isatty v0.0.12 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.0.0-20200930185726-fdedc70b468f // indirect
+)
@@ -0,0 +1,42 @@
+package evolution
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/slonegd-go/reversi/internal/game"
+)
+
+// archive записи сыгранных за эпоху партий, по одной на строку через табуляцию:
+// начальная позиция (game.FormatSetup), запись ходов и счёт зелёный:красный.
+// По начальной позиции партии со случайным стартом и закрытыми клетками
+// можно воспроизвести.
+type archive struct {
+	mutex sync.Mutex
+	file  *os.File
+}
+
+func openArchive(path string) (*archive, error) {
+	if err := os.MkdirAll(path, os.ModePerm); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filepath.Join(path, "games.txt"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &archive{file: file}, nil
+}
+
+func (archive *archive) write(played *game.Game, result game.Result) error {
+	archive.mutex.Lock()
+	defer archive.mutex.Unlock()
+	_, err := fmt.Fprintf(archive.file, "%s\t%s\t%d:%d\n",
+		game.FormatSetup(played.Initial()), game.FormatTranscript(result.Moves, result.Size), result.Green, result.Red)
+	return err
+}
+
+func (archive *archive) Close() error {
+	return archive.file.Close()
+}
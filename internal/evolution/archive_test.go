@@ -0,0 +1,82 @@
+package evolution
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player/random"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchive_reload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	variant := game.Variant{RandomMoves: 6, Seed: 3, Blocked: []string{"A1", "H8"}}
+	played := game.New(random.New(1), random.New(2), game.WithVariant(variant))
+	result := played.Start(context.Background())
+
+	games, err := openArchive(dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, games.write(played, result))
+	assert.NoError(t, games.Close())
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "games.txt"))
+	assert.NoError(t, err)
+	lines := strings.SplitAfter(strings.TrimSuffix(string(data), "\n"), "\n")
+	assert.Len(t, lines, 1)
+	record, err := parseArchived(lines[0])
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, result.Green, record.Green)
+	assert.Equal(t, result.Red, record.Red)
+
+	reloaded := game.New(random.New(1), random.New(2), game.WithSetup(record.Setup))
+	assert.NoError(t, reloaded.Load(record.Transcript, -1))
+	assert.Equal(t, played.Position(), reloaded.Position())
+	assert.Equal(t, result.Moves, reloaded.Moves())
+}
+
+func TestParseArchived(t *testing.T) {
+	_, err := parseArchived("f5d6 2:62\n")
+	assert.EqualError(t, err, `archive line must have setup, moves and score, got "f5d6 2:62\n"`)
+	_, err = parseArchived("-------- G\tf5\tscore")
+	assert.Error(t, err)
+}
+
+//
+//
+// helpers
+//
+//
+
+// archived партия из строки архива
+type archived struct {
+	Setup      string
+	Transcript string
+	Green, Red int
+}
+
+func parseArchived(line string) (archived, error) {
+	fields := strings.Split(strings.TrimSuffix(line, "\n"), "\t")
+	if len(fields) != 3 {
+		return archived{}, fmt.Errorf("archive line must have setup, moves and score, got %q", line)
+	}
+	result := archived{Setup: fields[0], Transcript: fields[1]}
+	if _, err := fmt.Sscanf(fields[2], "%d:%d", &result.Green, &result.Red); err != nil {
+		return archived{}, fmt.Errorf("parse score %q: %w", fields[2], err)
+	}
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package evolution
+
+import (
+	"context"
+	"log"
+	"math/rand"
+
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player"
+	"github.com/slonegd-go/reversi/internal/player/greedy"
+	"github.com/slonegd-go/reversi/internal/player/neural"
+	"github.com/slonegd-go/reversi/internal/player/random"
+)
+
+// evaluationGames партий с каждым простым соперником после эпохи
+const evaluationGames = 20
+
+// opponents простые соперники, по которым видно, научились ли игроки хоть чему-то
+var opponents = []struct {
+	name string
+	new  func() player.Player
+}{
+	{"random", func() player.Player { return random.New(rand.Int63()) }},
+	{"greedy", func() player.Player { return greedy.New(false) }},
+}
+
+// evaluate играет count партий игрока filename из каталога path с каждым
+// из opponents, поровну за каждый цвет, и возвращает долю побед по имени соперника.
+// Игрок загружается заново на каждую партию, поэтому не учится на них,
+// а его файл не меняется.
+func evaluate(path, filename string, size, count int, variant game.Variant, opts ...game.Option) map[string]float64 {
+	result := map[string]float64{}
+	for _, opponent := range opponents {
+		wins := 0
+		for i := 0; i < count; i++ {
+			v := variant
+			if v.RandomMoves > 0 {
+				v.Seed = rand.Int63()
+			}
+			var trained player.Player = frozen{neural.NewSize(path, filename, size)}
+			green, red := trained, opponent.new()
+			if i%2 != 0 {
+				green, red = red, green
+			}
+			gameOpts := append([]game.Option{game.WithSize(size), game.WithVariant(v)}, opts...)
+			played := game.New(green, red, gameOpts...).Start(context.Background())
+			if played.Winner == trained.Color() {
+				wins++
+			}
+		}
+		result[opponent.name] = float64(wins) / float64(count)
+		log.Printf("%s wins %.0f%% of %d games against %s", filename, result[opponent.name]*100, count, opponent.name)
+	}
+	return result
+}
+
+// frozen обученный игрок, который не учится на итоге партии
+type frozen struct {
+	*neural.Player
+}
+
+func (frozen) Notify(player.Result) {}
@@ -0,0 +1,35 @@
+package evolution
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player/neural"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "evaluate")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	trained := neural.NewSize(dir, "1_1", 6)
+	if !assert.NoError(t, trained.Save()) {
+		return
+	}
+	saved, err := ioutil.ReadFile(filepath.Join(dir, "1_1"))
+	assert.NoError(t, err)
+
+	got := evaluate(dir, "1_1", 6, 4, game.Variant{})
+	assert.Len(t, got, 2)
+	for _, name := range []string{"random", "greedy"} {
+		assert.True(t, got[name] >= 0 && got[name] <= 1, name)
+	}
+	after, err := ioutil.ReadFile(filepath.Join(dir, "1_1"))
+	assert.NoError(t, err)
+	assert.Equal(t, saved, after, "not trained on evaluation games")
+}
@@ -1,6 +1,7 @@
 package evolution
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"math/rand"
@@ -13,7 +14,13 @@ import (
 	"github.com/slonegd-go/reversi/internal/player/neural"
 )
 
-func Start() {
+// epochGames партий в эпохе
+const epochGames = 10000
+
+// Start обучает игроков на поле size x size эпоха за эпохой, играя по правилам
+// variant с остальными настройками opts. Для случайных стартов каждая партия
+// начинается со своей позиции.
+func Start(size int, variant game.Variant, opts ...game.Option) {
 
 	for epoch := 1; ; epoch++ {
 		log.Printf("start epoch #%d", epoch)
@@ -23,78 +30,97 @@ func Start() {
 			continue
 		}
 
-		// загрузить
-		path = filepath.Join(".", "players", fmt.Sprintf("epoch%d", epoch))
-		players := []*neural.Player{
-			neural.New(path, fmt.Sprintf("%d_1", epoch)),
-			neural.New(path, fmt.Sprintf("%d_2", epoch)),
-			neural.New(path, fmt.Sprintf("%d_3", epoch)),
-			neural.New(path, fmt.Sprintf("%d_4", epoch)),
-			neural.New(path, fmt.Sprintf("%d_5", epoch)),
-			neural.New(path, fmt.Sprintf("%d_6", epoch)),
-			neural.New(path, fmt.Sprintf("%d_7", epoch)),
-			neural.New(path, fmt.Sprintf("%d_8", epoch)),
-			neural.New(path, fmt.Sprintf("%d_9", epoch)),
+		if err := runEpoch(filepath.Join(".", "players"), epoch, size, epochGames, variant, opts...); err != nil {
+			log.Printf(err.Error())
+			return
 		}
+	}
+}
 
-		// определить сколько игр прошло
-		gameCount := 0
-		for _, player := range players {
-			gameCount += player.WinCount()
-		}
+// runEpoch доигрывает эпоху epoch из каталога dir до count партий
+// и создаёт игроков следующей эпохи из лучших
+func runEpoch(dir string, epoch, size, count int, variant game.Variant, opts ...game.Option) error {
+	// загрузить
+	path := filepath.Join(dir, fmt.Sprintf("epoch%d", epoch))
+	players := make([]*neural.Player, 0, 9)
+	for i := 1; i <= 9; i++ {
+		players = append(players, neural.NewSize(path, fmt.Sprintf("%d_%d", epoch, i), size))
+	}
+
+	games, err := openArchive(path)
+	if err != nil {
+		return err
+	}
+
+	// определить сколько игр прошло
+	gameCount := 0
+	for _, player := range players {
+		gameCount += player.WinCount()
+	}
 
-		// продолжить обучение
-		for ; gameCount < 10000; gameCount += 4 {
-			log.Printf("start %d game of %d epoch", gameCount, epoch)
-			plN := make([]int, 0, 8)
-			plN = append(plN, rand.Intn(len(players)))
-			for i := 1; i < 8; i++ {
-				for {
-					n := rand.Intn(len(players))
-					if exist(plN, n) {
-						continue
-					}
-					plN = append(plN, n)
-					break
+	// продолжить обучение
+	for ; gameCount < count; gameCount += 4 {
+		log.Printf("start %d game of %d epoch", gameCount, epoch)
+		plN := make([]int, 0, 8)
+		plN = append(plN, rand.Intn(len(players)))
+		for i := 1; i < 8; i++ {
+			for {
+				n := rand.Intn(len(players))
+				if exist(plN, n) {
+					continue
 				}
+				plN = append(plN, n)
+				break
 			}
+		}
 
-			var wg sync.WaitGroup
-			wg.Add(4)
-			for i := 0; i < 8; i += 2 {
-				go func(i int) {
-					currentGame := game.New(players[plN[i]], players[plN[i+1]], game.WithLogger(log.Printf))
-					currentGame.Start()
-					wg.Done()
-				}(i)
+		var wg sync.WaitGroup
+		wg.Add(4)
+		for i := 0; i < 8; i += 2 {
+			v := variant
+			if v.RandomMoves > 0 {
+				v.Seed = rand.Int63()
 			}
-			wg.Wait()
+			go func(i int) {
+				gameOpts := append([]game.Option{game.WithLogger(log.Printf), game.WithSize(size), game.WithVariant(v)}, opts...)
+				currentGame := game.New(players[plN[i]], players[plN[i+1]], gameOpts...)
+				result := currentGame.Start(context.Background())
+				if err := games.write(currentGame, result); err != nil {
+					log.Printf(err.Error())
+				}
+				wg.Done()
+			}(i)
 		}
+		wg.Wait()
+	}
+	games.Close()
 
-		// по окончанию определить лучших
-		sort.Slice(players, func(i, j int) bool {
-			return players[i].WinRatio() > players[j].WinRatio()
-		})
-
-		// сгенерировать новых
-		newEpoch := epoch + 1
-		regenerate := true
-		path = filepath.Join(".", "players", fmt.Sprintf("epoch%d", newEpoch))
-		if err := os.MkdirAll(path, os.ModePerm); err != nil {
-			log.Printf(err.Error())
-			return
-		}
+	// по окончанию определить лучших
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].WinRatio() > players[j].WinRatio()
+	})
 
-		players[0].CopyToFilename(path, fmt.Sprintf("%d_1", newEpoch))
-		players[0].CopyToFilename(path, fmt.Sprintf("%d_2", newEpoch), regenerate)
-		players[0].CopyToFilename(path, fmt.Sprintf("%d_3", newEpoch), regenerate)
-		players[1].CopyToFilename(path, fmt.Sprintf("%d_4", newEpoch))
-		players[1].CopyToFilename(path, fmt.Sprintf("%d_5", newEpoch), regenerate)
-		players[1].CopyToFilename(path, fmt.Sprintf("%d_6", newEpoch), regenerate)
-		players[2].CopyToFilename(path, fmt.Sprintf("%d_7", newEpoch))
-		players[2].CopyToFilename(path, fmt.Sprintf("%d_8", newEpoch), regenerate)
-		players[2].CopyToFilename(path, fmt.Sprintf("%d_9", newEpoch), regenerate)
+	// сгенерировать новых
+	newEpoch := epoch + 1
+	regenerate := true
+	path = filepath.Join(dir, fmt.Sprintf("epoch%d", newEpoch))
+	if err := os.MkdirAll(path, os.ModePerm); err != nil {
+		return err
 	}
+
+	players[0].CopyToFilename(path, fmt.Sprintf("%d_1", newEpoch))
+	players[0].CopyToFilename(path, fmt.Sprintf("%d_2", newEpoch), regenerate)
+	players[0].CopyToFilename(path, fmt.Sprintf("%d_3", newEpoch), regenerate)
+	players[1].CopyToFilename(path, fmt.Sprintf("%d_4", newEpoch))
+	players[1].CopyToFilename(path, fmt.Sprintf("%d_5", newEpoch), regenerate)
+	players[1].CopyToFilename(path, fmt.Sprintf("%d_6", newEpoch), regenerate)
+	players[2].CopyToFilename(path, fmt.Sprintf("%d_7", newEpoch))
+	players[2].CopyToFilename(path, fmt.Sprintf("%d_8", newEpoch), regenerate)
+	players[2].CopyToFilename(path, fmt.Sprintf("%d_9", newEpoch), regenerate)
+
+	// проверить лучшего на простых соперниках
+	evaluate(path, fmt.Sprintf("%d_1", newEpoch), size, evaluationGames, variant, opts...)
+	return nil
 }
 
 func exist(list []int, v int) bool {
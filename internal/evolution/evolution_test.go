@@ -0,0 +1,42 @@
+package evolution
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunEpoch_size(t *testing.T) {
+	dir, err := ioutil.TempDir("", "evolution")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, runEpoch(dir, 1, 6, 4, game.Variant{}))
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "epoch1", "games.txt"))
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	assert.Len(t, lines, 4)
+	for _, line := range lines {
+		record, err := parseArchived(line)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		position, _, err := game.ParseSetup(record.Setup)
+		assert.NoError(t, err)
+		assert.Equal(t, 6, position.Size())
+	}
+
+	for i := 1; i <= 9; i++ {
+		_, err := os.Stat(filepath.Join(dir, "epoch2", fmt.Sprintf("2_%d", i)))
+		assert.NoError(t, err, i)
+	}
+}
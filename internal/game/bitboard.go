@@ -0,0 +1,224 @@
+package game
+
+import "math/bits"
+
+// Bitboard битовая маска поля: бит i соответствует клетке i,
+// клетки нумеруются по строкам с A1. Два слова вмещают поле до 10x10.
+type Bitboard struct {
+	lo, hi uint64
+}
+
+func bit(cellN int) Bitboard {
+	if cellN < 64 {
+		return Bitboard{lo: 1 << uint(cellN)}
+	}
+	return Bitboard{hi: 1 << uint(cellN-64)}
+}
+
+func (b Bitboard) Has(cellN int) bool {
+	return !b.and(bit(cellN)).IsZero()
+}
+
+func (b Bitboard) Count() int {
+	return bits.OnesCount64(b.lo) + bits.OnesCount64(b.hi)
+}
+
+func (b Bitboard) IsZero() bool {
+	return b.lo == 0 && b.hi == 0
+}
+
+// Cells номера клеток по возрастанию
+func (b Bitboard) Cells() []int {
+	result := make([]int, 0, b.Count())
+	for lo := b.lo; lo != 0; lo &= lo - 1 {
+		result = append(result, bits.TrailingZeros64(lo))
+	}
+	for hi := b.hi; hi != 0; hi &= hi - 1 {
+		result = append(result, 64+bits.TrailingZeros64(hi))
+	}
+	return result
+}
+
+// each вызывает f для каждой клетки по возрастанию, не выделяя память
+func (b Bitboard) each(f func(cellN int)) {
+	for lo := b.lo; lo != 0; lo &= lo - 1 {
+		f(bits.TrailingZeros64(lo))
+	}
+	for hi := b.hi; hi != 0; hi &= hi - 1 {
+		f(64 + bits.TrailingZeros64(hi))
+	}
+}
+
+// First клетка с наименьшим номером, для пустой маски -1
+func (b Bitboard) First() int {
+	switch {
+	case b.lo != 0:
+		return bits.TrailingZeros64(b.lo)
+	case b.hi != 0:
+		return 64 + bits.TrailingZeros64(b.hi)
+	}
+	return -1
+}
+
+func (b Bitboard) and(o Bitboard) Bitboard    { return Bitboard{lo: b.lo & o.lo, hi: b.hi & o.hi} }
+func (b Bitboard) or(o Bitboard) Bitboard     { return Bitboard{lo: b.lo | o.lo, hi: b.hi | o.hi} }
+func (b Bitboard) andNot(o Bitboard) Bitboard { return Bitboard{lo: b.lo &^ o.lo, hi: b.hi &^ o.hi} }
+func (b Bitboard) not() Bitboard              { return Bitboard{lo: ^b.lo, hi: ^b.hi} }
+
+// shl сдвиг к старшим клеткам, 0 < n < 64
+func (b Bitboard) shl(n uint) Bitboard {
+	return Bitboard{lo: b.lo << n, hi: b.hi<<n | b.lo>>(64-n)}
+}
+
+// shr сдвиг к младшим клеткам, 0 < n < 64
+func (b Bitboard) shr(n uint) Bitboard {
+	return Bitboard{lo: b.lo>>n | b.hi<<(64-n), hi: b.hi >> n}
+}
+
+// geometry маски поля одного размера
+type geometry struct {
+	size      int
+	narrow    bool     // поле помещается в младшее слово
+	full      Bitboard // все клетки поля
+	maxFlips  int      // больше фишек соперника подряд между двумя клетками не поместится
+	centerTop int      // левая верхняя из четырёх центральных клеток
+	shifts    [8]shift // по направлениям
+}
+
+// shift сдвиг на одну клетку: на n бит к младшим или старшим клеткам
+// с маской, отбрасывающей ушедшие через край
+type shift struct {
+	n       uint
+	toLower bool
+	mask    Bitboard
+}
+
+var geometries = func() [MaxSize + 1]*geometry {
+	result := [MaxSize + 1]*geometry{}
+	for size := MinSize; size <= MaxSize; size += 2 {
+		g := &geometry{size: size, narrow: size*size <= 64, maxFlips: size - 2, centerTop: (size/2-1)*size + size/2 - 1}
+		var notFirst, notLast Bitboard // без первой и без последней колонки
+		for cellN := 0; cellN < size*size; cellN++ {
+			g.full = g.full.or(bit(cellN))
+			if cellN%size != 0 {
+				notFirst = notFirst.or(bit(cellN))
+			}
+			if cellN%size != size-1 {
+				notLast = notLast.or(bit(cellN))
+			}
+		}
+		n := uint(size)
+		g.shifts[Left] = shift{n: 1, toLower: true, mask: notLast}
+		g.shifts[Right] = shift{n: 1, mask: notFirst}
+		g.shifts[Up] = shift{n: n, toLower: true, mask: g.full}
+		g.shifts[Down] = shift{n: n, mask: g.full}
+		g.shifts[LeftUp] = shift{n: n + 1, toLower: true, mask: notLast}
+		g.shifts[RightUp] = shift{n: n - 1, toLower: true, mask: notFirst}
+		g.shifts[LeftDown] = shift{n: n - 1, mask: notLast}
+		g.shifts[RightDown] = shift{n: n + 1, mask: notFirst}
+		result[size] = g
+	}
+	return result
+}()
+
+// shift сдвигает все клетки на одну в направлении, отбрасывая ушедшие за край
+func (g *geometry) shift(b Bitboard, direction Direction) Bitboard {
+	shift := &g.shifts[direction]
+	if shift.toLower {
+		return b.shr(shift.n).and(shift.mask)
+	}
+	return b.shl(shift.n).and(shift.mask)
+}
+
+// legalMoves клетки, в которые может сходить владелец own
+func (g *geometry) legalMoves(own, opp Bitboard) Bitboard {
+	if g.narrow {
+		return Bitboard{lo: g.legalMoves64(own.lo, opp.lo)}
+	}
+	empty := g.full.andNot(own.or(opp))
+	var moves Bitboard
+	for _, direction := range directionList {
+		x := g.shift(own, direction).and(opp)
+		for i := 1; i < g.maxFlips; i++ {
+			x = x.or(g.shift(x, direction).and(opp))
+		}
+		moves = moves.or(g.shift(x, direction).and(empty))
+	}
+	return moves
+}
+
+// legalMoves64 то же для полей, которые помещаются в одно слово
+func (g *geometry) legalMoves64(own, opp uint64) uint64 {
+	empty := g.full.lo &^ (own | opp)
+	var moves uint64
+	for i := range g.shifts {
+		shift := &g.shifts[i]
+		n, mask := shift.n, shift.mask.lo
+		var x uint64
+		if shift.toLower {
+			x = own >> n & mask & opp
+			for i := 1; i < g.maxFlips; i++ {
+				x |= x >> n & mask & opp
+			}
+			moves |= x >> n & mask & empty
+		} else {
+			x = own << n & mask & opp
+			for i := 1; i < g.maxFlips; i++ {
+				x |= x << n & mask & opp
+			}
+			moves |= x << n & mask & empty
+		}
+	}
+	return moves
+}
+
+// flipsDirection фишки соперника, которые перевернутся в одном направлении
+// при ходе в клетку cellN
+func (g *geometry) flipsDirection(own, opp Bitboard, cellN int, direction Direction) Bitboard {
+	var flips Bitboard
+	x := g.shift(bit(cellN), direction)
+	for ; !x.and(opp).IsZero(); x = g.shift(x, direction) {
+		flips = flips.or(x)
+	}
+	if x.and(own).IsZero() {
+		return Bitboard{}
+	}
+	return flips
+}
+
+// flips все фишки соперника, которые перевернутся при ходе в клетку cellN
+func (g *geometry) flips(own, opp Bitboard, cellN int) Bitboard {
+	if g.narrow {
+		return Bitboard{lo: g.flips64(own.lo, opp.lo, cellN)}
+	}
+	var result Bitboard
+	for _, direction := range directionList {
+		result = result.or(g.flipsDirection(own, opp, cellN, direction))
+	}
+	return result
+}
+
+// flips64 то же для полей, которые помещаются в одно слово
+func (g *geometry) flips64(own, opp uint64, cellN int) uint64 {
+	var result uint64
+	start := uint64(1) << uint(cellN)
+	for i := range g.shifts {
+		shift := &g.shifts[i]
+		n, mask := shift.n, shift.mask.lo
+		var flips uint64
+		x := start
+		if shift.toLower {
+			for x = x >> n & mask; x&opp != 0; x = x >> n & mask {
+				flips |= x
+			}
+		} else {
+			for x = x << n & mask; x&opp != 0; x = x << n & mask {
+				flips |= x
+			}
+		}
+		if x&own != 0 {
+			result |= flips
+		}
+	}
+	return result
+}
@@ -0,0 +1,296 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/slonegd-go/reversi/internal/player"
+	"github.com/slonegd-go/reversi/internal/player/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitboard_matchLegacy(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for n := 0; n < 200; n++ {
+		game := New(&cli.Player{}, &cli.Player{})
+		color := player.Green
+		for passes := 0; passes < 2; color = color.Opponent() {
+			board := legacyBoard(game.position.Cells())
+			enabled := game.enabledSteps(color)
+			if !assert.Equal(t, board.enabledSteps(color), enabled, game.String()) {
+				return
+			}
+			moves := []int{}
+			for i, ok := range enabled {
+				if ok {
+					moves = append(moves, i)
+				}
+			}
+			if len(moves) == 0 {
+				passes++
+				if !game.endCheck() {
+					assert.NoError(t, game.play(Move{Color: color, Cell: Pass}))
+				}
+				continue
+			}
+			passes = 0
+			cellN := moves[rnd.Intn(len(moves))]
+			board.step(cellN, color)
+			assert.NoError(t, game.Step(color, cellName(cellN, 8)))
+			if !assert.Equal(t, []player.Color(board), game.position.Cells()) {
+				return
+			}
+		}
+	}
+}
+
+func BenchmarkEnabledSteps(b *testing.B) {
+	game := g("C3:Red,D3:Green,E3:Red,F6:Green,C6:Red,E6:Red,B2:Green,G7:Red")
+	b.Run("legacy", func(b *testing.B) {
+		board := legacyBoard(game.position.Cells())
+		for i := 0; i < b.N; i++ {
+			board.enabledSteps(player.Green)
+		}
+	})
+	b.Run("bitboard", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			game.enabledSteps(player.Green)
+		}
+	})
+	b.Run("bitboard mask", func(b *testing.B) {
+		own, opp := game.position.bitboards(player.Green)
+		for i := 0; i < b.N; i++ {
+			geometries[8].legalMoves(own, opp)
+		}
+	})
+}
+
+func BenchmarkStep(b *testing.B) {
+	start := g("C3:Red,D3:Green,E3:Red,F6:Green,C6:Red,E6:Red,B2:Green,G7:Red")
+	b.Run("legacy", func(b *testing.B) {
+		cells := start.position.Cells()
+		board := make(legacyBoard, len(cells))
+		for i := 0; i < b.N; i++ {
+			copy(board, cells)
+			board.step(n("D6"), player.Green)
+		}
+	})
+	b.Run("bitboard", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			own, opp := start.position.bitboards(player.Green)
+			flipped := geometries[8].flips(own, opp, n("D6"))
+			own = own.or(bit(n("D6"))).or(flipped)
+			opp = opp.andNot(flipped)
+		}
+	})
+}
+
+//
+//
+// helpers and mocks
+//
+//
+
+// legacyBoard прежняя реализация на слайсе, для сравнения и бенчмарков
+type legacyBoard []player.Color
+
+func (board legacyBoard) enabledSteps(color player.Color) []bool {
+	result := make([]bool, 0, 64)
+	for i, cell := range board {
+		if cell != player.Empty {
+			result = append(result, false)
+			continue
+		}
+		enabled := false
+		for _, direction := range directionList {
+			if board.count(i, direction, color) != 0 {
+				enabled = true
+				break
+			}
+		}
+		result = append(result, enabled)
+	}
+	return result
+}
+
+func (board legacyBoard) step(cellN int, color player.Color) {
+	directions := []Direction{}
+	for _, direction := range directionList {
+		if board.count(cellN, direction, color) != 0 {
+			directions = append(directions, direction)
+		}
+	}
+	board[cellN] = color
+	for _, direction := range directions {
+		board.count(cellN, direction, color, func(i int) { board[i] = color })
+	}
+}
+
+func (board legacyBoard) count(cellN int, direction Direction, color player.Color, change ...func(i int)) int {
+	changeFunc := func(_ int) {}
+	if len(change) != 0 {
+		changeFunc = change[0]
+	}
+	count := 0
+
+	switch direction {
+	case Up:
+		if cellN < 8 { // border
+			return 0
+		}
+		i := cellN - 8
+		for ; i < 0 || board[i] != color; i -= 8 {
+			if i < 8 || board[i] == player.Empty {
+				return 0
+			}
+			changeFunc(i)
+			count++
+		}
+		if i < 0 {
+			return 0
+		}
+
+	case Down:
+		if cellN > 63-8 { // border
+			return 0
+		}
+		i := cellN + 8
+		for ; i > 63 || board[i] != color; i += 8 {
+			if i > 63-8 || board[i] == player.Empty {
+				return 0
+			}
+			changeFunc(i)
+			count++
+		}
+		if i > 63 {
+			return 0
+		}
+
+	case Left:
+		if cellN%8 == 0 { // border
+			return 0
+		}
+		i := cellN - 1
+		for ; i < 0 || board[i] != color; i-- {
+			if i < 0 || i%8 == 0 || board[i] == player.Empty {
+				return 0
+			}
+			changeFunc(i)
+			count++
+		}
+		if i < 0 {
+			return 0
+		}
+
+	case Right:
+		if cellN%8 == 7 { // border
+			return 0
+		}
+		i := cellN + 1
+		for ; i > 63 || board[i] != color; i++ {
+			if i > 63 || i%8 == 7 || board[i] == player.Empty {
+				return 0
+			}
+			changeFunc(i)
+			count++
+		}
+		if i > 63 {
+			return 0
+		}
+
+	case LeftUp:
+		if cellN < 8 || cellN%8 == 0 { // border
+			return 0
+		}
+		i := cellN - 9
+		for ; i < 0 || board[i] != color; i -= 9 {
+			if i%8 == 0 || i < 8 || board[i] == player.Empty {
+				return 0
+			}
+			changeFunc(i)
+			count++
+		}
+		if i < 0 {
+			return 0
+		}
+
+	case RightUp:
+		if cellN < 8 || cellN%8 == 7 { // border
+			return 0
+		}
+		i := cellN - 7
+		for ; i < 0 || board[i] != color; i -= 7 {
+			if i%8 == 7 || i < 8 || board[i] == player.Empty {
+				return 0
+			}
+			changeFunc(i)
+			count++
+		}
+		if i < 0 {
+			return 0
+		}
+
+	case RightDown:
+		if cellN > 63-8 || cellN%8 == 7 { // border
+			return 0
+		}
+		i := cellN + 9
+		for ; i > 63 || board[i] != color; i += 9 {
+			if i%8 == 7 || i > 63-8 || board[i] == player.Empty {
+				return 0
+			}
+			changeFunc(i)
+			count++
+		}
+		if i > 63 {
+			return 0
+		}
+
+	case LeftDown:
+		if cellN > 63-8 || cellN%8 == 0 { // border
+			return 0
+		}
+		i := cellN + 7
+		for ; i > 63 || board[i] != color; i += 7 {
+			if i%8 == 0 || i > 63-8 || board[i] == player.Empty {
+				return 0
+			}
+			changeFunc(i)
+			count++
+		}
+		if i > 63 {
+			return 0
+		}
+
+	} // switch direction
+
+	return count
+}
+
+func TestGeometry_wide(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, size := range []int{4, 6, 8} {
+		narrow := geometries[size]
+		wide := *narrow
+		wide.narrow = false
+		for n := 0; n < 50; n++ {
+			position, _ := NewPositionSize(size)
+			color := player.Green
+			for !position.IsTerminal() {
+				own, opp := position.bitboards(color)
+				legal := narrow.legalMoves(own, opp)
+				if !assert.Equal(t, legal, wide.legalMoves(own, opp), "size %d", size) {
+					return
+				}
+				for _, cellN := range legal.Cells() {
+					assert.Equal(t, narrow.flips(own, opp, cellN), wide.flips(own, opp, cellN), "size %d", size)
+				}
+				if !legal.IsZero() {
+					cells := legal.Cells()
+					position, _ = position.Apply(Move{Color: color, Cell: cells[rnd.Intn(len(cells))]})
+				}
+				color = color.Opponent()
+			}
+		}
+	}
+}
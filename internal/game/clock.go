@@ -0,0 +1,167 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// TimeControl контроль времени: запас на партию с добавлением за каждый ход
+// или фиксированное время на ход. Нулевое значение - время не ограничено.
+type TimeControl struct {
+	Base      time.Duration // на всю партию
+	Increment time.Duration // добавляется после каждого своего хода
+	PerMove   time.Duration // на каждый ход, если задано, Base и Increment не используются
+}
+
+func (control TimeControl) limited() bool {
+	return control.Base > 0 || control.PerMove > 0
+}
+
+// start время на первый ход
+func (control TimeControl) start() time.Duration {
+	if control.PerMove > 0 {
+		return control.PerMove
+	}
+	return control.Base
+}
+
+func (control TimeControl) String() string {
+	switch {
+	case control.PerMove > 0:
+		return fmt.Sprintf("%v per move", control.PerMove)
+	case control.Base > 0:
+		return fmt.Sprintf("%v+%v", control.Base, control.Increment)
+	}
+	return "unlimited"
+}
+
+// Clock время игрока перед его ходом
+type Clock struct {
+	Remaining time.Duration // осталось на ход или до конца партии
+	Opponent  time.Duration // осталось у соперника
+	Control   TimeControl
+}
+
+// Budget ограничивает ход игрока, который думает не дольше limit (0 - без предела).
+// При времени на ход игрок получает 9/10 его, при запасе на партию - долю,
+// рассчитанную примерно на половину пустых клеток position, и половину добавки.
+// Без часов и предела ctx только можно отменить.
+func (clock Clock) Budget(ctx context.Context, position Position, limit time.Duration) (context.Context, context.CancelFunc) {
+	if clock.Remaining > 0 {
+		share := clock.Remaining * 9 / 10
+		if clock.Control.PerMove == 0 {
+			share = clock.Remaining/time.Duration(position.Empties().Count()/2+1) + clock.Control.Increment/2
+		}
+		if limit == 0 || share < limit {
+			limit = share
+		}
+	}
+	if limit == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, limit)
+}
+
+// ClockPlayer игрок с функцией хода, который распределяет своё время.
+// Время сообщается ему перед каждым ходом, игрокам Mover оно передаётся в Move.
+type ClockPlayer interface {
+	SetClock(Clock)
+}
+
+// WithTimeControl ограничивает время игроков. Игрок, у которого кончилось
+// время, проигрывает.
+func WithTimeControl(control TimeControl) Option {
+	return func(opts *Options) {
+		opts.control = control
+	}
+}
+
+// Remaining сколько времени осталось у игрока, 0 если время не ограничено
+func (game *Game) Remaining(color player.Color) time.Duration {
+	return game.remaining[color]
+}
+
+// TimeControl контроль времени игры
+func (game *Game) TimeControl() TimeControl {
+	return game.control
+}
+
+var errTimeOver = errors.New("time is over")
+
+// ask просит игрока сходить, следя за временем. Ход ждут в отдельной
+// горутине, поэтому игрок, который не следит за ctx, не блокирует игру.
+// Если время игрока кончилось, возвращается errTimeOver,
+// если он исчерпал попытки сходить - errTooManyAttempts.
+// Отмена или срок ctx вызывающего - не конец времени игрока, возвращается ctx.Err().
+func (game *Game) ask(ctx context.Context, mover Mover, color player.Color) (Move, error) {
+	if game.control.PerMove > 0 {
+		game.remaining[color] = game.control.PerMove // время на ход каждый раз полное, что бы ни было до него
+	}
+	clock := Clock{Remaining: game.remaining[color], Opponent: game.remaining[color.Opponent()], Control: game.control}
+	parent := ctx                          // срок вызывающего - отмена игры, а не конец времени игрока
+	ctx, cancel := context.WithCancel(ctx) // чтобы остановить игрока, исчерпавшего попытки
+	defer cancel()
+	if game.remaining != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, clock.Remaining)
+		defer cancel()
+	}
+
+	type answer struct {
+		move Move
+		err  error
+	}
+	answers := make(chan answer, 1)
+	attempts := make(chan IllegalAttempt)
+	position, legal := game.position, game.position.LegalMoves(color)
+	begin := time.Now()
+	go func() {
+		move, err := mover.Move(withAttempts(ctx, attempts), position, legal, clock)
+		answers <- answer{move: move, err: err}
+	}()
+
+	for {
+		select {
+		case attempt := <-attempts:
+			if game.violation(attempt) {
+				return Move{}, errTooManyAttempts
+			}
+		case a := <-answers:
+			if parent.Err() != nil {
+				return a.move, parent.Err()
+			}
+			if game.remaining != nil && !game.spend(color, time.Since(begin), a.err == nil && a.move.Cell >= 0) {
+				return a.move, errTimeOver
+			}
+			return a.move, a.err
+		case <-ctx.Done():
+			if parent.Err() != nil {
+				return Move{}, parent.Err()
+			}
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && game.remaining != nil {
+				return Move{}, errTimeOver
+			}
+			return Move{}, ctx.Err()
+		}
+	}
+}
+
+// spend списывает время хода, moved - фишка поставлена, а не пропуск или отмена.
+// Время на ход (PerMove) не добавляется, оно выставляется заново в ask.
+func (game *Game) spend(color player.Color, elapsed time.Duration, moved bool) bool {
+	remaining := game.remaining[color] - elapsed
+	if remaining <= 0 {
+		return false
+	}
+	if moved {
+		remaining += game.control.Increment
+	}
+	game.remaining[color] = remaining
+	game.log("%s player time left %v", color, remaining)
+	game.emit(ClockUpdated{Color: color, Remaining: remaining})
+	return true
+}
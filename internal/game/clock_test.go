@@ -0,0 +1,141 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/slonegd-go/reversi/internal/player"
+	"github.com/stretchr/testify/assert"
+)
+
+const passBoard = "D4:Empty,E4:Empty,D5:Empty,E5:Empty,A1:Green,B1:Red,H8:Green,H7:Red"
+
+func TestGame_Start_clock(t *testing.T) {
+	p1, p2 := &clockPlayer{mockPlayer: mockPlayer{steps: []string{"C1", "H6"}}}, &clockPlayer{}
+	game := New(p1, p2, WithTimeControl(TimeControl{Base: time.Second, Increment: 100 * time.Millisecond}))
+	fill(game, passBoard)
+	result := game.Start(context.Background())
+	assert.Equal(t, player.Green, result.Winner)
+	assert.Equal(t, Normal, result.Reason)
+	assert.Len(t, p1.clocks, 2)
+	assert.Equal(t, Clock{Remaining: time.Second, Opponent: time.Second, Control: game.TimeControl()}, p1.clocks[0])
+	assert.True(t, p1.clocks[1].Remaining > time.Second, "increment")
+	assert.True(t, game.Remaining(player.Green) > time.Second, "increment")
+	assert.True(t, game.Remaining(player.Red) <= time.Second, "red only passed, no increment")
+}
+
+func TestGame_Start_timeout(t *testing.T) {
+	tests := map[string]struct {
+		green player.Player
+	}{
+		"slow":  {green: &clockPlayer{mockPlayer: mockPlayer{steps: []string{"C1", "H6"}}, delay: 200 * time.Millisecond}},
+		"stuck": {green: &stuckPlayer{interrupted: make(chan struct{}, 1)}},
+	}
+	for name, tt := range tests {
+		red := &mockPlayer{}
+		game := New(tt.green, red, WithTimeControl(TimeControl{PerMove: 20 * time.Millisecond}))
+		fill(game, passBoard)
+		result := game.Start(context.Background())
+		assert.Equal(t, player.Red, result.Winner, name)
+		assert.Equal(t, Timeout, result.Reason, name)
+		assert.Equal(t, player.Win, red.result, name)
+		assert.Contains(t, result.String(), "win 2:2 by timeout", name)
+	}
+}
+
+func TestGame_Start_perMove(t *testing.T) {
+	takeBack := Move{Cell: TakeBack}
+	green := &clockMover{mover: mover{moves: []Move{{Cell: n("C1")}, takeBack, {Cell: n("C1")}, {Cell: n("H6")}}}, delay: 30 * time.Millisecond}
+	red := &mover{moves: []Move{{Cell: Pass}, {Cell: Pass}}}
+	control := TimeControl{PerMove: 200 * time.Millisecond}
+	game := New(green, red, WithTimeControl(control))
+	fill(game, passBoard)
+	result := game.Start(context.Background())
+	assert.Equal(t, Normal, result.Reason)
+	assert.Equal(t, "[C1 pass H6]", fmt.Sprint(result.Moves))
+	assert.Len(t, green.clocks, 4)
+	for i, clock := range green.clocks {
+		assert.Equal(t, control.PerMove, clock.Remaining, "move %d: full time after move, pass and take back", i+1)
+	}
+}
+
+func TestGame_Start_deadline(t *testing.T) {
+	green, red := &clockPlayer{mockPlayer: mockPlayer{result: -1}, delay: time.Hour}, &mockPlayer{result: -1}
+	game := New(green, red, WithTimeControl(TimeControl{Base: time.Hour}))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	result := game.Start(ctx)
+	assert.Equal(t, Aborted, result.Reason, "deadline of caller, not of player")
+	assert.Equal(t, player.Result(-1), red.result, "not notified")
+	assert.Equal(t, player.Result(-1), green.result, "not notified")
+}
+
+func TestGame_Start_autoPass(t *testing.T) {
+	green, red := &mockPlayer{steps: []string{"C1", "H6"}}, &clockMover{delay: time.Hour}
+	game := New(green, red, WithTimeControl(TimeControl{PerMove: 20 * time.Millisecond}))
+	fill(game, passBoard)
+	result := game.Start(context.Background())
+	assert.Equal(t, Normal, result.Reason)
+	assert.Equal(t, "[C1 pass H6]", fmt.Sprint(result.Moves))
+	assert.Empty(t, red.clocks, "red is not asked")
+}
+
+func TestClock_Budget(t *testing.T) {
+	position := NewPosition() // 60 пустых
+	tests := map[string]struct {
+		clock Clock
+		limit time.Duration
+		want  time.Duration
+	}{
+		"unlimited":  {want: 0},
+		"limit only": {limit: time.Second, want: time.Second},
+		"per move":   {clock: Clock{Remaining: time.Second, Control: TimeControl{PerMove: time.Second}}, want: 900 * time.Millisecond},
+		"game share": {clock: Clock{Remaining: 31 * time.Second, Control: TimeControl{Base: time.Minute, Increment: 2 * time.Second}}, want: 2 * time.Second},
+		"limit less": {clock: Clock{Remaining: time.Hour, Control: TimeControl{PerMove: time.Hour}}, limit: time.Second, want: time.Second},
+	}
+	for name, tt := range tests {
+		begin := time.Now()
+		ctx, cancel := tt.clock.Budget(context.Background(), position, tt.limit)
+		deadline, ok := ctx.Deadline()
+		assert.Equal(t, tt.want != 0, ok, name)
+		if ok {
+			assert.InDelta(t, float64(tt.want), float64(deadline.Sub(begin)), float64(50*time.Millisecond), name)
+		}
+		cancel()
+		assert.Error(t, ctx.Err(), name)
+	}
+}
+
+func TestTimeControl_String(t *testing.T) {
+	assert.Equal(t, "unlimited", TimeControl{}.String())
+	assert.Equal(t, "5m0s+2s", TimeControl{Base: 5 * time.Minute, Increment: 2 * time.Second}.String())
+	assert.Equal(t, "1s per move", TimeControl{PerMove: time.Second}.String())
+}
+
+type clockPlayer struct {
+	mockPlayer
+	delay  time.Duration
+	clocks []Clock
+}
+
+func (p *clockPlayer) SetClock(clock Clock) { p.clocks = append(p.clocks, clock) }
+
+// clockMover игрок второй версии, который думает delay и запоминает время перед ходами
+type clockMover struct {
+	mover
+	delay  time.Duration
+	clocks []Clock
+}
+
+func (p *clockMover) Move(ctx context.Context, position Position, legal []Move, clock Clock) (Move, error) {
+	p.clocks = append(p.clocks, clock)
+	time.Sleep(p.delay)
+	return p.mover.Move(ctx, position, legal, clock)
+}
+
+func (p *clockPlayer) Step(cells []player.Color, enabled []bool, step func(string) error) {
+	time.Sleep(p.delay)
+	p.mockPlayer.Step(cells, enabled, step)
+}
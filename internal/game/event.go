@@ -0,0 +1,113 @@
+package game
+
+import (
+	"context"
+	"time"
+
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// Event событие игры для наблюдателей: Started, MovePlayed, Passed, TakenBack,
+// IllegalAttempt, Penalized, ClockUpdated или GameOver
+type Event interface {
+	isEvent()
+}
+
+// Started игра началась
+type Started struct {
+	Position Position
+	Turn     player.Color
+	Variant  Variant
+	Control  TimeControl
+}
+
+// MovePlayed игрок поставил фишку
+type MovePlayed struct {
+	Move     Move
+	Flipped  Bitboard
+	Position Position // после хода
+}
+
+// Passed игрок пропустил ход
+type Passed struct {
+	Color player.Color
+}
+
+// TakenBack игрок отменил свой последний ход
+type TakenBack struct {
+	Color    player.Color
+	Position Position // после отмены
+}
+
+// IllegalAttempt игрок попытался сделать недопустимый ход.
+// Для нераспознанной клетки в Move указан пропуск, причина в Err.
+type IllegalAttempt struct {
+	Move Move
+	Err  error
+}
+
+// ClockUpdated у игрока списано время хода
+type ClockUpdated struct {
+	Color     player.Color
+	Remaining time.Duration
+}
+
+// GameOver игра закончена или остановлена
+type GameOver struct {
+	Result Result
+}
+
+func (Started) isEvent()        {}
+func (MovePlayed) isEvent()     {}
+func (Passed) isEvent()         {}
+func (TakenBack) isEvent()      {}
+func (IllegalAttempt) isEvent() {}
+func (ClockUpdated) isEvent()   {}
+func (GameOver) isEvent()       {}
+
+// Observer получает события в горутине игры по порядку,
+// поэтому не должен надолго её задерживать
+type Observer func(Event)
+
+// WithObserver подписывает наблюдателя на события игры
+func WithObserver(observer Observer) Option {
+	return func(opts *Options) {
+		opts.observers = append(opts.observers, observer)
+	}
+}
+
+// Subscribe подписывает наблюдателя на события игры, можно и во время игры
+func (game *Game) Subscribe(observer Observer) {
+	game.mutex.Lock()
+	defer game.mutex.Unlock()
+	game.observers = append(game.observers, observer)
+}
+
+func (game *Game) emit(event Event) {
+	game.mutex.Lock()
+	observers := game.observers
+	game.mutex.Unlock()
+	for _, observer := range observers {
+		observer(event)
+	}
+}
+
+// attemptsKey ключ контекста, через который адаптер сообщает игре
+// о недопустимых ходах, которые он вернул игроку ошибкой
+type attemptsKey struct{}
+
+func withAttempts(ctx context.Context, attempts chan<- IllegalAttempt) context.Context {
+	return context.WithValue(ctx, attemptsKey{}, attempts)
+}
+
+// reportAttempt передаёт игре недопустимый ход, если она его ждёт
+func reportAttempt(ctx context.Context, attempt IllegalAttempt) {
+	attempts, ok := ctx.Value(attemptsKey{}).(chan<- IllegalAttempt)
+	if !ok {
+		return
+	}
+	select {
+	case attempts <- attempt:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,64 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/slonegd-go/reversi/internal/player"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGame_events(t *testing.T) {
+	events := []Event{}
+	p1, p2 := &mockPlayer{steps: []string{"A1", "C1", "H6"}}, &mockPlayer{}
+	game := New(p1, p2, WithObserver(func(event Event) { events = append(events, event) }),
+		WithTimeControl(TimeControl{Base: time.Minute}))
+	fill(game, passBoard)
+	later := 0
+	game.Subscribe(func(Event) { later++ })
+	result := game.Start(context.Background())
+
+	kinds := []string{}
+	for _, event := range events {
+		kinds = append(kinds, fmt.Sprintf("%T", event))
+	}
+	assert.Equal(t, []string{
+		"game.Started",
+		"game.IllegalAttempt", "game.ClockUpdated", "game.MovePlayed",
+		"game.Passed",
+		"game.ClockUpdated", "game.MovePlayed",
+		"game.GameOver",
+	}, kinds)
+	assert.Equal(t, len(events), later)
+
+	attempt := events[1].(IllegalAttempt)
+	assert.Equal(t, Move{Color: player.Green, Cell: n("A1")}, attempt.Move)
+	assert.EqualError(t, attempt.Err, "cell not empty")
+
+	played := events[3].(MovePlayed)
+	assert.Equal(t, Move{Color: player.Green, Cell: n("C1")}, played.Move)
+	assert.Equal(t, []int{n("B1")}, played.Flipped.Cells())
+	assert.Equal(t, player.Green, played.Position.Cell(n("B1")))
+
+	assert.Equal(t, Passed{Color: player.Red}, events[4], "without clock")
+	assert.Equal(t, result, events[len(events)-1].(GameOver).Result)
+}
+
+func TestGame_events_takeBack(t *testing.T) {
+	events := []Event{}
+	game := New(&mockPlayer{steps: []string{"E6", player.Undo, "F5"}}, &mockPlayer{steps: []string{"F6"}},
+		WithObserver(func(event Event) { events = append(events, event) }))
+	ctx, cancel := context.WithCancel(context.Background())
+	game.Subscribe(func(event Event) {
+		if played, ok := event.(MovePlayed); ok && played.Move.Cell == n("F5") {
+			cancel()
+		}
+	})
+	result := game.Start(ctx)
+	assert.Equal(t, Aborted, result.Reason)
+	assert.Equal(t, "[F5]", fmt.Sprint(result.Moves))
+	assert.IsType(t, TakenBack{}, events[3])
+	assert.Equal(t, NewPosition(), events[3].(TakenBack).Position)
+}
@@ -0,0 +1,139 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// FuzzGame_Step играет ходами из данных: первый байт выбирает размер поля,
+// каждый следующий - клетку и цвет, байт 0xFF отменяет ход. Допустимые и
+// недопустимые ходы, в том числе не в свою очередь, проверяются по простой
+// эталонной реализации.
+func FuzzGame_Step(f *testing.F) {
+	f.Add([]byte{2, 20, 11, 0x80 | 44, 0xFF, 43})
+	f.Add([]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15})
+	f.Add([]byte{3, 0xFF, 44, 53, 0x80 | 35, 0xFF, 0xFF, 99, 0x80 | 98})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if len(data) == 0 {
+			return
+		}
+		size := MinSize + int(data[0])%((MaxSize-MinSize)/2+1)*2
+		game := New(&mockPlayer{}, &mockPlayer{}, WithSize(size))
+		reference := newReferenceBoard(game.Position().Cells(), size)
+		history := []referenceBoard{}
+		turn := player.Green
+		for i, b := range data[1:] {
+			if b == 0xFF {
+				if err := game.Undo(); (err == nil) != (len(history) > 0) {
+					t.Fatalf("step %d undo: %v", i, err)
+				}
+				if len(history) > 0 {
+					reference = history[len(history)-1]
+					history = history[:len(history)-1]
+					turn = turn.Opponent()
+				}
+			} else {
+				color := player.Green
+				if b&0x80 != 0 {
+					color = player.Red
+				}
+				cellN := int(b&0x7F) % (size * size)
+				err := game.Step(color, cellName(cellN, size))
+				legal := color == turn && reference.legal(cellN, color)
+				if (err == nil) != legal {
+					t.Fatalf("step %d %s %s: got error %v, reference legal %t\n%s", i, color, cellName(cellN, size), err, legal, game)
+				}
+				if legal {
+					history = append(history, reference)
+					reference = reference.play(cellN, color)
+					turn = turn.Opponent()
+				}
+			}
+			compareReference(t, i, game, reference)
+		}
+	})
+}
+
+func compareReference(t *testing.T, i int, game *Game, reference referenceBoard) {
+	position := game.Position()
+	cells := position.Cells()
+	for cellN, color := range reference.cells {
+		if cells[cellN] != color {
+			t.Fatalf("step %d: cell %s is %s, reference %s\n%s", i, cellName(cellN, reference.size), cells[cellN], color, game)
+		}
+	}
+	for _, color := range []player.Color{player.Green, player.Red} {
+		legal := position.Legal(color)
+		for cellN := range reference.cells {
+			if legal.Has(cellN) != reference.legal(cellN, color) {
+				t.Fatalf("step %d: %s legal %s is %t, reference %t\n%s", i, color, cellName(cellN, reference.size), legal.Has(cellN), !legal.Has(cellN), game)
+			}
+		}
+	}
+	if position.Hash() != position.rehash().Hash() {
+		t.Fatalf("step %d: incremental hash differs", i)
+	}
+}
+
+// referenceBoard эталон: клетки по строкам и проверка границ по координатам
+type referenceBoard struct {
+	cells []player.Color
+	size  int
+}
+
+func newReferenceBoard(cells []player.Color, size int) referenceBoard {
+	return referenceBoard{cells: append([]player.Color(nil), cells...), size: size}
+}
+
+func (board referenceBoard) at(row, column int) (player.Color, bool) {
+	if row < 0 || row >= board.size || column < 0 || column >= board.size {
+		return player.Empty, false
+	}
+	return board.cells[row*board.size+column], true
+}
+
+// flips клетки, которые перевернёт ход, по всем восьми направлениям
+func (board referenceBoard) flips(cellN int, color player.Color) []int {
+	if board.cells[cellN] != player.Empty {
+		return nil
+	}
+	result := []int{}
+	row, column := cellN/board.size, cellN%board.size
+	for dr := -1; dr <= 1; dr++ {
+		for dc := -1; dc <= 1; dc++ {
+			if dr == 0 && dc == 0 {
+				continue
+			}
+			line := []int{}
+			r, c := row+dr, column+dc
+			for {
+				cell, ok := board.at(r, c)
+				if !ok || cell == player.Empty {
+					line = nil
+					break
+				}
+				if cell == color {
+					break
+				}
+				line = append(line, r*board.size+c)
+				r, c = r+dr, c+dc
+			}
+			result = append(result, line...)
+		}
+	}
+	return result
+}
+
+func (board referenceBoard) legal(cellN int, color player.Color) bool {
+	return len(board.flips(cellN, color)) > 0
+}
+
+func (board referenceBoard) play(cellN int, color player.Color) referenceBoard {
+	result := newReferenceBoard(board.cells, board.size)
+	for _, flip := range board.flips(cellN, color) {
+		result.cells[flip] = color
+	}
+	result.cells[cellN] = color
+	return result
+}
@@ -1,10 +1,13 @@
 package game
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"math/rand"
 	"strconv"
-	"strings"
+	"sync"
+	"time"
 
 	"github.com/fatih/color"
 
@@ -12,14 +15,37 @@ import (
 )
 
 type Game struct {
-	cells     []player.Color
+	start     Position     // позиция, с которой началась игра
+	first     player.Color // кто ходит первым со start
+	position  Position
 	stepCellN int
-	players   []player.Player
+	history   []Record
+	ply       int // сколько ходов истории сыграно, остальные отменены
+	turn      player.Color
+	players   []Mover
+	variant   Variant
+	control   TimeControl
+	remaining map[player.Color]time.Duration // nil, если время не ограничено
 	log       func(string, ...interface{})
+	mutex     sync.Mutex // для observers
+	observers []Observer
+	policy    IllegalPolicy
+	attempts  int              // недопустимых ходов подряд у того, кто ходит
+	illegal   []IllegalAttempt // все недопустимые ходы партии
+	random    *rand.Rand       // для PenaltyRandom
+	renderer  Renderer
 }
 
 type Options struct {
-	log func(string, ...interface{})
+	log       func(string, ...interface{})
+	position  Position
+	turn      player.Color
+	variant   Variant
+	control   TimeControl
+	observers []Observer
+	illegal   IllegalPolicy
+	renderer  Renderer
+	err       error // первая ошибка настроек, такая настройка не применяется
 }
 
 type Option func(*Options)
@@ -30,29 +56,81 @@ func WithLogger(log func(string, ...interface{})) Option {
 	}
 }
 
-func New(p1, p2 player.Player, opts ...Option) *Game {
-	cells := make([]player.Color, 64)
-	cells[27] = player.Green
-	cells[28] = player.Red
-	cells[35] = player.Red
-	cells[36] = player.Green
+// WithPosition начинает игру с позиции, первым ходит turn
+func WithPosition(position Position, turn player.Color) Option {
+	return func(opts *Options) {
+		opts.position = position
+		opts.turn = turn
+	}
+}
+
+// WithSize игра на поле size x size, размер можно проверить через CheckSize.
+// Недопустимый размер пишется в лог и не применяется.
+func WithSize(size int) Option {
+	return func(opts *Options) {
+		position, err := NewPositionSize(size)
+		if err != nil {
+			opts.fail(err)
+			return
+		}
+		opts.position = position
+	}
+}
+
+func (opts *Options) fail(err error) {
+	if opts.err == nil {
+		opts.err = err
+	}
+}
 
+func New(p1, p2 player.Player, opts ...Option) *Game {
 	p1.SetColor(player.Green)
 	p2.SetColor(player.Red)
 
 	options := &Options{
-		log: func(string, ...interface{}) {},
+		log:      func(string, ...interface{}) {},
+		position: NewPosition(),
+		turn:     player.Green,
+		renderer: DefaultRenderer(),
 	}
 
 	for _, opt := range opts {
 		opt(options)
 	}
 
+	if options.err != nil {
+		options.log("options: %v", options.err)
+	}
+	if err := options.variant.Check(options.position); err != nil {
+		options.log("variant: %v, blocked cells ignored", err)
+		options.variant.Blocked = nil
+	}
+	start, turn := options.variant.apply(options.position, options.turn)
+	for _, p := range []player.Player{p1, p2} {
+		if p, ok := p.(VariantPlayer); ok {
+			p.SetVariant(options.variant)
+		}
+	}
+
 	game := &Game{
-		cells:     cells,
+		start:     start,
+		first:     turn,
+		position:  start,
 		stepCellN: -1,
-		players:   []player.Player{p1, p2},
+		turn:      turn,
+		players:   []Mover{Adapt(p1), Adapt(p2)},
+		variant:   options.variant,
+		control:   options.control,
 		log:       options.log,
+		observers: options.observers,
+		policy:    options.illegal,
+		renderer:  options.renderer,
+	}
+	if options.control.limited() {
+		game.remaining = map[player.Color]time.Duration{
+			player.Green: options.control.start(),
+			player.Red:   options.control.start(),
+		}
 	}
 
 	game.log(game.String())
@@ -60,81 +138,165 @@ func New(p1, p2 player.Player, opts ...Option) *Game {
 	return game
 }
 
-func (game *Game) Start() string {
+// Start играет до конца партии. Если ctx отменён, игра останавливается
+// с причиной Aborted, игрокам итог не сообщается.
+func (game *Game) Start(ctx context.Context) Result {
 	game.log(game.String())
-	for i := 0; i < 64; i++ {
-		currentPlayer := game.players[i%2]
-
-		game.log("%s player step:", currentPlayer.Color())
-		enabledCells := game.enabledSteps(currentPlayer.Color())
-		currentPlayer.Step(game.cells, enabledCells, func(position string) error {
-			err := game.Step(currentPlayer.Color(), position)
-			if err != nil {
+	game.emit(Started{Position: game.position, Turn: game.turn, Variant: game.variant, Control: game.control})
+	for {
+		if ctx.Err() != nil {
+			return game.abort()
+		}
+		if game.endCheck() {
+			return game.finish(Normal)
+		}
+
+		color := game.turn
+		if !game.position.HasMoves(color) { // игрока не спрашиваем и время не идёт
+			if err := game.play(Move{Color: color, Cell: Pass}); err == nil {
+				game.played()
+			}
+			continue
+		}
+		game.log("%s player step:", color)
+		move, err := game.ask(ctx, game.player(color), color)
+		switch {
+		case ctx.Err() != nil:
+			return game.abort()
+		case errors.Is(err, errTimeOver):
+			game.log("%s player time is over", color)
+			return game.lose(color, Timeout)
+		case errors.Is(err, errTooManyAttempts):
+			if result, over := game.penalize(color); over {
+				return result
+			}
+			continue
+		case err != nil:
+			game.log("%s player: %s", color, err)
+			return game.lose(color, Forfeit)
+		}
+
+		move.Color, move.Forced = color, false // вынужденный пропуск делает только игра
+		switch move.Cell {
+		case Resign:
+			game.log("%s player resigns", color)
+			return game.lose(color, Resignation)
+		case TakeBack:
+			if err := game.undoTurn(color); err != nil {
 				game.log(err.Error())
+				continue
+			}
+			game.attempts = 0
+			game.emit(TakenBack{Color: color, Position: game.position})
+		default:
+			if err := game.play(move); err == nil {
+				game.played()
+			} else if game.violation(IllegalAttempt{Move: move, Err: err}) {
+				if result, over := game.penalize(color); over {
+					return result
+				}
 			}
-			return err
-		})
-
-		end := game.endCheck(currentPlayer.Color())
-		if end {
-			winPlayer, losePlayer := game.compute()
-			result := fmt.Sprintf("%s player win", winPlayer.Color())
-			game.log(result)
-			winPlayer.Notify(player.Win)
-			losePlayer.Notify(player.Lose)
-			return result
 		}
 	}
-	return "error"
 }
 
-func (game *Game) endCheck(color player.Color) bool {
-	otherPlayer := player.Green
-	if color == player.Green {
-		otherPlayer = player.Red
+// played сообщает о последнем сделанном ходе или пропуске
+func (game *Game) played() {
+	game.attempts = 0
+	record := game.history[game.ply-1]
+	if record.Move.IsPass() {
+		game.log("%s player pass", record.Move.Color)
+		game.emit(Passed{Color: record.Move.Color})
+		return
 	}
-	for _, b := range game.enabledSteps(otherPlayer) {
-		if b {
-			return false
-		}
+	game.log(game.String())
+	game.emit(MovePlayed{Move: record.Move, Flipped: record.Flipped, Position: game.position})
+}
+
+func (game *Game) endCheck() bool {
+	return game.position.IsTerminal()
+}
+
+func (game *Game) player(color player.Color) Mover {
+	if color == player.Red {
+		return game.players[1]
 	}
-	return true
+	return game.players[0]
 }
 
-func (game *Game) compute() (win player.Player, lose player.Player) {
-	win = game.players[0]
-	lose = game.players[1]
-	greenCount, redCount := 0, 0
-	for _, cell := range game.cells {
-		switch cell {
-		case player.Green:
-			greenCount++
-		case player.Red:
-			redCount++
-		}
+// Initial позиция, с которой началась игра, и кто в ней ходит
+func (game *Game) Initial() (Position, player.Color) {
+	return game.start, game.first
+}
+
+// Variant правила, по которым идёт игра
+func (game *Game) Variant() Variant {
+	return game.variant
+}
+
+// Position текущая позиция
+func (game *Game) Position() Position {
+	return game.position
+}
+
+// finish подводит итог по фишкам и сообщает его игрокам
+func (game *Game) finish(reason Reason) Result {
+	result := game.result(reason)
+	result.Winner = game.variant.winner(result.Green, result.Red)
+	return game.notify(result)
+}
+
+// abort останавливает игру до конца партии
+func (game *Game) abort() Result {
+	result := game.result(Aborted)
+	game.log(result.String())
+	game.emit(GameOver{Result: result})
+	return result
+}
+
+// lose заканчивает игру поражением loser независимо от фишек
+func (game *Game) lose(loser player.Color, reason Reason) Result {
+	result := game.result(reason)
+	result.Winner = loser.Opponent()
+	return game.notify(result)
+}
+
+func (game *Game) result(reason Reason) Result {
+	result := Result{
+		Green:   game.position.Count(player.Green),
+		Red:     game.position.Count(player.Red),
+		Reason:  reason,
+		Moves:   game.Moves(),
+		Size:    game.position.Size(),
+		Illegal: game.illegal,
 	}
-	game.log("%s %d:%d %s", green("green"), greenCount, redCount, red("red"))
-	if redCount > greenCount {
-		win, lose = lose, win
+	game.log("%s %d:%d %s", green("green"), result.Green, result.Red, red("red"))
+	return result
+}
+
+// notify сообщает итог игрокам
+func (game *Game) notify(result Result) Result {
+	game.log(result.String())
+	game.emit(GameOver{Result: result})
+
+	for _, p := range game.players {
+		switch result.Winner {
+		case player.Empty:
+			p.Notify(player.Draw)
+		case p.Color():
+			p.Notify(player.Win)
+		default:
+			p.Notify(player.Lose)
+		}
 	}
-	return win, lose
+	return result
 }
 
 func (game *Game) enabledSteps(color player.Color) []bool {
-	result := make([]bool, 0, 64)
-	for i, cell := range game.cells {
-		if cell != player.Empty {
-			result = append(result, false)
-			continue
-		}
-		enabled := false
-		for _, direction := range directionList {
-			if game.count(i, direction, color) != 0 {
-				enabled = true
-				break
-			}
-		}
-		result = append(result, enabled)
+	legal := game.position.Legal(color)
+	result := make([]bool, game.position.Size()*game.position.Size())
+	for i := range result {
+		result[i] = legal.Has(i)
 	}
 	return result
 }
@@ -144,242 +306,125 @@ var (
 	red   = color.New(color.FgRed).SprintFunc()
 )
 
-func (game Game) String() string {
-	var builder strings.Builder
-	builder.Grow(101)
-	builder.WriteString("\n\\ A B C D E F G H\n")
-	for i := 0; i < 8; i++ {
-		builder.WriteString(strconv.Itoa(i + 1))
-		for j := 0; j < 8; j++ {
-			number := i*8 + j
-			if game.stepCellN == number {
-				builder.WriteString(" x")
-				continue
-			}
-			switch game.cells[i*8+j] {
-			case player.Empty:
-				builder.WriteString("  ")
-			case player.Green:
-				builder.WriteString(green(" G"))
-			case player.Red:
-				builder.WriteString(red(" R"))
-			}
-		}
-		builder.WriteRune('\n')
+func (game *Game) String() string {
+	return game.renderer.Render(game.View())
+}
+
+// View текущая позиция для Renderer
+func (game *Game) View() View {
+	view := View{Position: game.position, Turn: game.turn, Last: -1, Marker: game.stepCellN}
+	if game.ply > 0 {
+		view.Last = game.history[game.ply-1].Move.Cell // для пропуска -1
 	}
-	return builder.String()
+	return view
 }
 
+// Step ставит фишку игрока в клетку с названием position, например "E3"
 func (game *Game) Step(color player.Color, position string) error {
+	_, err := game.Play(color, position)
+	return err
+}
+
+// Play ставит фишку как Step и возвращает сделанный ход с перевёрнутыми
+// фишками по направлениям, например чтобы показать перевороты по очереди
+func (game *Game) Play(color player.Color, position string) (Played, error) {
 	if color != player.Green && color != player.Red {
-		return errors.New("only green and red state available")
+		return Played{}, errors.New("only green and red state available")
+	}
+	if color != game.turn {
+		return Played{}, fmt.Errorf("%s turn", game.turn.Name())
 	}
 
-	cellN, err := parseCellN(position)
+	cellN, err := parseCellN(position, game.position.Size())
 	if err != nil {
-		return fmt.Errorf("parse cell number: %w", err)
+		return Played{}, fmt.Errorf("parse cell number: %w", err)
 	}
 
 	game.stepCellN = cellN
 
 	game.log(game.String())
 
-	if game.cells[cellN] != player.Empty {
-		return errors.New("cell not empty")
+	if game.position.Blocked().Has(cellN) {
+		return Played{}, errors.New("cell blocked")
 	}
-
-	directions := []direction{}
-	for _, direction := range directionList {
-		if game.count(cellN, direction, color) != 0 {
-			directions = append(directions, direction)
-		}
+	if game.position.Cell(cellN) != player.Empty {
+		return Played{}, errors.New("cell not empty")
 	}
-	if len(directions) == 0 {
-		return errors.New("unavailable step")
+
+	move := Move{Color: color, Cell: cellN}
+	next, flipped := game.position.Apply(move)
+	if flipped.IsZero() {
+		return Played{}, errors.New("unavailable step")
 	}
+	lines := game.position.FlipLines(move)
 
 	game.stepCellN = -1
-	game.cells[cellN] = color
-	for _, direction := range directions {
-		game.count(cellN, direction, color, game.changeTo(color))
-	}
+	game.position = next
+	game.record(move, flipped)
 
 	game.log(game.String())
-	return nil
+	game.emit(MovePlayed{Move: move, Flipped: flipped, Position: next})
+	return Played{Move: move, Flipped: flipped, Lines: lines}, nil
 }
 
-func (game *Game) changeTo(color player.Color) func(i int) {
-	return func(i int) {
-		game.cells[i] = color
-	}
+func (game *Game) count(cellN int, direction Direction, color player.Color) int {
+	own, opp := game.position.bitboards(color)
+	return game.position.geometry.flipsDirection(own, opp, cellN, direction).Count()
 }
 
-func (game *Game) count(cellN int, direction direction, color player.Color, change ...func(i int)) int {
-	changeFunc := func(_ int) {}
-	if len(change) != 0 {
-		changeFunc = change[0]
+func parseCellN(position string, size int) (int, error) {
+	if len(position) < 2 {
+		return 0, fmt.Errorf("position must be from A1 to %s, got: %s", cellName(size*size-1, size), position)
+	}
+	column := int(position[0]) - 'A'
+	line, err := strconv.Atoi(position[1:])
+	if err != nil || position[1] < '1' || position[1] > '9' || column < 0 || column >= size || line < 1 || line > size {
+		return 0, fmt.Errorf("position must be from A1 to %s, got: %s", cellName(size*size-1, size), position)
 	}
-	count := 0
-
-	switch direction {
-	case up:
-		if cellN < 8 { // border
-			return 0
-		}
-		i := cellN - 8
-		for ; i < 0 || game.cells[i] != color; i -= 8 {
-			if i < 8 || game.cells[i] == player.Empty {
-				return 0
-			}
-			changeFunc(i)
-			count++
-		}
-		if i < 0 {
-			return 0
-		}
-
-	case down:
-		if cellN > 63-8 { // border
-			return 0
-		}
-		i := cellN + 8
-		for ; i > 63 || game.cells[i] != color; i += 8 {
-			if i > 63-8 || game.cells[i] == player.Empty {
-				return 0
-			}
-			changeFunc(i)
-			count++
-		}
-		if i > 63 {
-			return 0
-		}
-
-	case left:
-		if cellN%8 == 0 { // border
-			return 0
-		}
-		i := cellN - 1
-		for ; i < 0 || game.cells[i] != color; i-- {
-			if i < 0 || i%8 == 0 || game.cells[i] == player.Empty {
-				return 0
-			}
-			changeFunc(i)
-			count++
-		}
-		if i < 0 {
-			return 0
-		}
-
-	case right:
-		if cellN%8 == 7 { // border
-			return 0
-		}
-		i := cellN + 1
-		for ; i > 63 || game.cells[i] != color; i++ {
-			if i > 63 || i%8 == 7 || game.cells[i] == player.Empty {
-				return 0
-			}
-			changeFunc(i)
-			count++
-		}
-		if i > 63 {
-			return 0
-		}
-
-	case leftup:
-		if cellN < 8 || cellN%8 == 0 { // border
-			return 0
-		}
-		i := cellN - 9
-		for ; i < 0 || game.cells[i] != color; i -= 9 {
-			if i%8 == 0 || i < 8 || game.cells[i] == player.Empty {
-				return 0
-			}
-			changeFunc(i)
-			count++
-		}
-		if i < 0 {
-			return 0
-		}
-
-	case rightup:
-		if cellN < 8 || cellN%8 == 7 { // border
-			return 0
-		}
-		i := cellN - 7
-		for ; i < 0 || game.cells[i] != color; i -= 7 {
-			if i%8 == 7 || i < 8 || game.cells[i] == player.Empty {
-				return 0
-			}
-			changeFunc(i)
-			count++
-		}
-		if i < 0 {
-			return 0
-		}
-
-	case rightdown:
-		if cellN > 63-8 || cellN%8 == 7 { // border
-			return 0
-		}
-		i := cellN + 9
-		for ; i > 63 || game.cells[i] != color; i += 9 {
-			if i%8 == 7 || i > 63-8 || game.cells[i] == player.Empty {
-				return 0
-			}
-			changeFunc(i)
-			count++
-		}
-		if i > 63 {
-			return 0
-		}
-
-	case leftdown:
-		if cellN > 63-8 || cellN%8 == 0 { // border
-			return 0
-		}
-		i := cellN + 7
-		for ; i > 63 || game.cells[i] != color; i += 7 {
-			if i%8 == 0 || i > 63-8 || game.cells[i] == player.Empty {
-				return 0
-			}
-			changeFunc(i)
-			count++
-		}
-		if i > 63 {
-			return 0
-		}
-
-	} // switch direction
 
-	return count
+	cellN := column + (line-1)*size
+	return cellN, nil
 }
 
-func parseCellN(position string) (int, error) {
-	if len(position) != 2 {
-		return 0, fmt.Errorf("position must be from A1 to H8, got: %s", position)
-	}
-	column := position[0] - byte('A')
-	line := position[1] - byte('1')
-	if column > 7 || line > 7 {
-		return 0, fmt.Errorf("position must be from A1 to H8, got: %s", position)
-	}
-
-	cellN := column + line*8
-	return int(cellN), nil
+func cellName(cellN int, size int) string {
+	return string(rune('A'+cellN%size)) + strconv.Itoa(cellN/size+1)
 }
 
-type direction int
+// Direction направление от клетки на поле, вверх - к первой строке
+type Direction int
 
 const (
-	left direction = iota
-	right
-	up
-	down
-	leftup
-	rightup
-	leftdown
-	rightdown
+	Left Direction = iota
+	Right
+	Up
+	Down
+	LeftUp
+	RightUp
+	LeftDown
+	RightDown
 )
 
-var directionList = []direction{left, right, up, down, leftup, rightup, leftdown, rightdown}
+var directionList = []Direction{Left, Right, Up, Down, LeftUp, RightUp, LeftDown, RightDown}
+
+func (direction Direction) String() string {
+	switch direction {
+	case Left:
+		return "left"
+	case Right:
+		return "right"
+	case Up:
+		return "up"
+	case Down:
+		return "down"
+	case LeftUp:
+		return "left up"
+	case RightUp:
+		return "right up"
+	case LeftDown:
+		return "left down"
+	case RightDown:
+		return "right down"
+	default:
+		return fmt.Sprintf("undefined(%d)", int(direction))
+	}
+}
@@ -1,9 +1,12 @@
 package game
 
 import (
+	"context"
+	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/fatih/color"
 	"github.com/slonegd-go/reversi/internal/player"
 	"github.com/slonegd-go/reversi/internal/player/cli"
 	"github.com/stretchr/testify/assert"
@@ -17,41 +20,53 @@ func TestGame_Step(t *testing.T) {
 		wantErr  string
 		wantGame string
 	}{
-		"E3 green down must ok": {game: g(""), color: Green, position: "E3", wantGame: `
+		"E6 green up must ok": {game: g(""), color: Green, position: "E6", wantGame: `
 \ A B C D E F G H
 1                
 2                
-3         G      
-4       G G      
-5       R G      
-6                
+3                
+4       R G      
+5       G G      
+6         G      
 7                
 8                
 `},
 
-		"D3 green down must not ok": {game: g(""), color: Green, position: "D3",
+		"D6 green up must not ok": {game: g(""), color: Green, position: "D6",
 			wantErr: "unavailable step", wantGame: `
 \ A B C D E F G H
 1                
 2                
-3       x        
-4       G R      
-5       R G      
-6                
+3                
+4       R G      
+5       G R      
+6       x        
 7                
 8                
 `},
-		"several directions": {game: g("D2:Green,E2:Green,F2:Red,D3:Green"), color: Red, position: "C2",
-			wantGame: `
+		"D6 red out of turn must not ok": {game: g(""), color: Red, position: "D6",
+			wantErr: "green turn", wantGame: `
 \ A B C D E F G H
 1                
-2     R R R R    
-3       R        
-4       G R      
-5       R G      
+2                
+3                
+4       R G      
+5       G R      
 6                
 7                
 8                
+`},
+		"several directions": {game: turn(g("D7:Green,E7:Green,F7:Red,D6:Green"), Red), color: Red, position: "C7",
+			wantGame: `
+\ A B C D E F G H
+1                
+2                
+3                
+4       R G      
+5       G R      
+6       R        
+7     R R R R    
+8                
 `},
 	}
 
@@ -75,48 +90,48 @@ func TestGame_count(t *testing.T) {
 		name      string
 		game      *Game
 		cellN     int
-		direction direction
+		direction Direction
 		color     player.Color
 		want      int
 		wantGame  string
 	}{
-		"↖ border": {game: g(""), cellN: n("A1"), color: Red, direction: up, want: 0},
-		"← border": {game: g(""), cellN: n("A1"), color: Red, direction: left, want: 0},
+		"↖ border": {game: g(""), cellN: n("A1"), color: Red, direction: Up, want: 0},
+		"← border": {game: g(""), cellN: n("A1"), color: Red, direction: Left, want: 0},
 		// up cases
-		"↑ bad with empty":  {game: g("B1:Empty,B2:Green,B3:Green"), cellN: n("B4"), color: Red, direction: up, want: 0},
-		"↑ bad with border": {game: g("B1:Green,B2:Green,B3:Green"), cellN: n("B4"), color: Red, direction: up, want: 0},
-		"↑ good":            {game: g("B1:Red,B2:Green,B3:Green"), cellN: n("B4"), color: Red, direction: up, want: 2},
+		"↑ bad with empty":  {game: g("B1:Empty,B2:Green,B3:Green"), cellN: n("B4"), color: Red, direction: Up, want: 0},
+		"↑ bad with border": {game: g("B1:Green,B2:Green,B3:Green"), cellN: n("B4"), color: Red, direction: Up, want: 0},
+		"↑ good":            {game: g("B1:Red,B2:Green,B3:Green"), cellN: n("B4"), color: Red, direction: Up, want: 2},
 		// down cases
-		"↓ bad with empty":  {game: g("B8:Empty,B7:Green,B6:Green"), cellN: n("B5"), color: Red, direction: down, want: 0},
-		"↓ bad with border": {game: g("B8:Green,B7:Green,B6:Green"), cellN: n("B5"), color: Red, direction: down, want: 0},
-		"↓ good":            {game: g("B8:Red,B7:Green,B6:Green"), cellN: n("B5"), color: Red, direction: down, want: 2},
+		"↓ bad with empty":  {game: g("B8:Empty,B7:Green,B6:Green"), cellN: n("B5"), color: Red, direction: Down, want: 0},
+		"↓ bad with border": {game: g("B8:Green,B7:Green,B6:Green"), cellN: n("B5"), color: Red, direction: Down, want: 0},
+		"↓ good":            {game: g("B8:Red,B7:Green,B6:Green"), cellN: n("B5"), color: Red, direction: Down, want: 2},
 		// left cases
-		"← bad with empty":  {game: g("A2:Empty,B2:Red,C2:Red"), cellN: n("D2"), color: Green, direction: left, want: 0},
-		"← bad with border": {game: g("A2:Red,B2:Red,C2:Red"), cellN: n("D2"), color: Green, direction: left, want: 0},
-		"← good":            {game: g("A2:Green,B2:Red,C2:Red"), cellN: n("D2"), color: Green, direction: left, want: 2},
+		"← bad with empty":  {game: g("A2:Empty,B2:Red,C2:Red"), cellN: n("D2"), color: Green, direction: Left, want: 0},
+		"← bad with border": {game: g("A2:Red,B2:Red,C2:Red"), cellN: n("D2"), color: Green, direction: Left, want: 0},
+		"← good":            {game: g("A2:Green,B2:Red,C2:Red"), cellN: n("D2"), color: Green, direction: Left, want: 2},
 		// right cases
-		"→ bad with empty":  {game: g("H6:Empty,G6:Empty,F6:Red"), cellN: n("E6"), color: Green, direction: right, want: 0},
-		"→ bad with border": {game: g("H6:Red,G6:Red,F6:Red"), cellN: n("E6"), color: Green, direction: right, want: 0},
-		"→ good":            {game: g("G6:Green,F6:Red"), cellN: n("E6"), color: Green, direction: right, want: 1},
+		"→ bad with empty":  {game: g("H6:Empty,G6:Empty,F6:Red"), cellN: n("E6"), color: Green, direction: Right, want: 0},
+		"→ bad with border": {game: g("H6:Red,G6:Red,F6:Red"), cellN: n("E6"), color: Green, direction: Right, want: 0},
+		"→ good":            {game: g("G6:Green,F6:Red"), cellN: n("E6"), color: Green, direction: Right, want: 1},
 		// left up cases
-		"↖ good":            {game: g("B1:Green,C2:Red"), cellN: n("D3"), color: Green, direction: leftup, want: 1},
-		"↖ bad up border":   {game: g("B1:Red,C2:Red"), cellN: n("D3"), color: Green, direction: leftup, want: 0},
-		"↖ bad left border": {game: g("A2:Red,B3:Red"), cellN: n("C4"), color: Green, direction: leftup, want: 0},
-		"↖ bad empty":       {game: g("B2:Empty,C3:Red"), cellN: n("D4"), color: Green, direction: leftup, want: 0},
+		"↖ good":            {game: g("B1:Green,C2:Red"), cellN: n("D3"), color: Green, direction: LeftUp, want: 1},
+		"↖ bad up border":   {game: g("B1:Red,C2:Red"), cellN: n("D3"), color: Green, direction: LeftUp, want: 0},
+		"↖ bad left border": {game: g("A2:Red,B3:Red"), cellN: n("C4"), color: Green, direction: LeftUp, want: 0},
+		"↖ bad empty":       {game: g("B2:Empty,C3:Red"), cellN: n("D4"), color: Green, direction: LeftUp, want: 0},
 		// right up cases
-		"↗ good":             {game: g("G1:Green,F2:Red"), cellN: n("E3"), color: Green, direction: rightup, want: 1},
-		"↗ bad up border":    {game: g("G1:Red,F2:Red"), cellN: n("E3"), color: Green, direction: rightup, want: 0},
-		"↗ bad right border": {game: g("H2:Red,G3:Red"), cellN: n("F4"), color: Green, direction: rightup, want: 0},
-		"↗ bad empty":        {game: g("H2:Empty,G3:Red"), cellN: n("F4"), color: Green, direction: rightup, want: 0},
+		"↗ good":             {game: g("G1:Green,F2:Red"), cellN: n("E3"), color: Green, direction: RightUp, want: 1},
+		"↗ bad up border":    {game: g("G1:Red,F2:Red"), cellN: n("E3"), color: Green, direction: RightUp, want: 0},
+		"↗ bad right border": {game: g("H2:Red,G3:Red"), cellN: n("F4"), color: Green, direction: RightUp, want: 0},
+		"↗ bad empty":        {game: g("H2:Empty,G3:Red"), cellN: n("F4"), color: Green, direction: RightUp, want: 0},
 		// right down cases
-		"↘ good":             {game: g("H7:Green,G6:Red"), cellN: n("F5"), color: Green, direction: rightdown, want: 1},
-		"↘ bad right border": {game: g("H7:Red,G6:Red"), cellN: n("F5"), color: Green, direction: rightdown, want: 0},
-		"↘ bad down border":  {game: g("G8:Red,F7:Red"), cellN: n("E5"), color: Green, direction: rightdown, want: 0},
+		"↘ good":             {game: g("H7:Green,G6:Red"), cellN: n("F5"), color: Green, direction: RightDown, want: 1},
+		"↘ bad right border": {game: g("H7:Red,G6:Red"), cellN: n("F5"), color: Green, direction: RightDown, want: 0},
+		"↘ bad down border":  {game: g("G8:Red,F7:Red"), cellN: n("E5"), color: Green, direction: RightDown, want: 0},
 		// left down cases
-		"↙ good":            {game: g("A7:Green,B6:Red"), cellN: n("C5"), color: Green, direction: leftdown, want: 1},
-		"↙ bad left border": {game: g("A7:Red,B6:Red"), cellN: n("C5"), color: Green, direction: leftdown, want: 0},
-		"↙ bad down border": {game: g("B8:Red,C7:Red"), cellN: n("D6"), color: Green, direction: leftdown, want: 0},
-		"↙ bad empty":       {game: g("B8:Empty,C7:Red"), cellN: n("D6"), color: Green, direction: leftdown, want: 0},
+		"↙ good":            {game: g("A7:Green,B6:Red"), cellN: n("C5"), color: Green, direction: LeftDown, want: 1},
+		"↙ bad left border": {game: g("A7:Red,B6:Red"), cellN: n("C5"), color: Green, direction: LeftDown, want: 0},
+		"↙ bad down border": {game: g("B8:Red,C7:Red"), cellN: n("D6"), color: Green, direction: LeftDown, want: 0},
+		"↙ bad empty":       {game: g("B8:Empty,C7:Red"), cellN: n("D6"), color: Green, direction: LeftDown, want: 0},
 	}
 	for name, tt := range tests {
 		got := tt.game.count(tt.cellN, tt.direction, tt.color)
@@ -124,6 +139,89 @@ func TestGame_count(t *testing.T) {
 	}
 }
 
+func TestGame_Start(t *testing.T) {
+	tests := map[string]struct {
+		board     string
+		green     []string
+		red       []string
+		wantMoves string
+		want      Result
+		wantGreen player.Result
+		wantRed   player.Result
+	}{
+		"red pass": {
+			board:     "D4:Empty,E4:Empty,D5:Empty,E5:Empty,A1:Green,B1:Red,H8:Green,H7:Red",
+			green:     []string{"C1", "H6"},
+			wantMoves: "[C1 pass H6]",
+			want:      Result{Winner: Green, Green: 6, Red: 0, Size: 8},
+			wantGreen: player.Win,
+			wantRed:   player.Lose,
+		},
+		"green pass": {
+			board:     "D4:Empty,E4:Empty,D5:Empty,E5:Empty,A1:Red,B1:Green",
+			red:       []string{"C1"},
+			wantMoves: "[pass C1]",
+			want:      Result{Winner: Red, Green: 0, Red: 3, Size: 8},
+			wantGreen: player.Lose,
+			wantRed:   player.Win,
+		},
+		"draw when nobody can move": {
+			board:     "D4:Empty,E4:Empty,D5:Empty,E5:Empty,A1:Red,H8:Green",
+			wantMoves: "[]",
+			want:      Result{Winner: player.Empty, Green: 1, Red: 1, Size: 8},
+			wantGreen: player.Draw,
+			wantRed:   player.Draw,
+		},
+	}
+	for name, tt := range tests {
+		p1, p2 := &mockPlayer{steps: tt.green}, &mockPlayer{steps: tt.red}
+		game := New(p1, p2)
+		fill(game, tt.board)
+		result := game.Start(context.Background())
+		assert.Equal(t, tt.wantMoves, fmt.Sprint(result.Moves), name)
+		result.Moves = nil
+		assert.Equal(t, tt.want, result, name)
+		assert.Equal(t, tt.wantGreen, p1.result, name)
+		assert.Equal(t, tt.wantRed, p2.result, name)
+		assert.Empty(t, p1.steps, name)
+		assert.Empty(t, p2.steps, name)
+	}
+}
+
+func TestGame_Step_size(t *testing.T) {
+	game := New(&mockPlayer{}, &mockPlayer{}, WithSize(10))
+	assert.EqualError(t, game.Step(Green, "K1"), "parse cell number: position must be from A1 to J10, got: K1")
+	assert.EqualError(t, game.Step(Green, "J10"), "unavailable step")
+	assert.NoError(t, game.Step(Green, "F7"))
+	f6, _ := parseCellN("F6", 10)
+	assert.Equal(t, player.Green, game.position.Cell(f6))
+}
+
+func TestGame_Play(t *testing.T) {
+	game := g("A6:Green,B6:Red,C6:Red,E6:Red,F6:Red,G6:Green,C5:Red,B4:Green,D5:Red,D4:Green")
+	played, err := game.Play(Green, "D6")
+	assert.NoError(t, err)
+	assert.Equal(t, Move{Color: Green, Cell: n("D6")}, played.Move)
+	assert.Equal(t, []Line{
+		{Direction: Left, Cells: []int{n("C6"), n("B6")}},
+		{Direction: Right, Cells: []int{n("E6"), n("F6")}},
+		{Direction: Up, Cells: []int{n("D5")}},
+		{Direction: LeftUp, Cells: []int{n("C5")}},
+	}, played.Lines)
+	assert.Equal(t, []int{n("C5"), n("D5"), n("B6"), n("C6"), n("E6"), n("F6")}, played.Flipped.Cells())
+	assert.Equal(t, []Record{{Move: played.Move, Flipped: played.Flipped}}, game.History())
+
+	played, err = game.Play(Red, "D6")
+	assert.EqualError(t, err, "cell not empty")
+	assert.Equal(t, Played{}, played)
+
+	noColor := color.NoColor
+	color.NoColor = false // как в терминале
+	defer func() { color.NoColor = noColor }()
+	_, err = game.Play(Green, "E3")
+	assert.EqualError(t, err, "red turn", "без раскраски")
+}
+
 //
 //
 // helpers and mocks
@@ -131,12 +229,12 @@ func TestGame_count(t *testing.T) {
 //
 
 func cellN(s string) int {
-	result, _ := parseCellN(s)
+	result, _ := parseCellN(s, 8)
 	return result
 }
 
 func n(s string) int {
-	result, _ := parseCellN(s)
+	result, _ := parseCellN(s, 8)
 	return result
 }
 
@@ -151,18 +249,42 @@ func c(s string) player.Color {
 }
 
 func g(description string) *Game {
-	result := New(&cli.Player{}, &cli.Player{})
+	result := New(&cli.Player{}, &cli.Player{}, WithRenderer(PlainRenderer{}))
+	fill(result, description)
+	return result
+}
+
+// turn передаёт ход color, как будто соперник уже сходил
+func turn(game *Game, color player.Color) *Game {
+	game.turn = color
+	return game
+}
+
+func fill(game *Game, description string) {
 	if description == "" {
-		return result
+		return
 	}
 	cells := strings.Split(description, ",")
 	for _, cell := range cells {
 		nColor := strings.Split(cell, ":")
-		result.cells[cellN(nColor[0])] = c(nColor[1])
+		game.position = game.position.with(cellN(nColor[0]), c(nColor[1]))
 	}
+}
 
-	return result
+type mockPlayer struct {
+	color  player.Color
+	steps  []string
+	result player.Result
+}
+
+func (p *mockPlayer) Step(_ []player.Color, _ []bool, step func(string) error) {
+	position := p.steps[0]
+	p.steps = p.steps[1:]
+	step(position)
 }
+func (p *mockPlayer) Notify(v player.Result)  { p.result = v }
+func (p *mockPlayer) SetColor(v player.Color) { p.color = v }
+func (p *mockPlayer) Color() player.Color     { return p.color }
 
 var (
 	Green = player.Green
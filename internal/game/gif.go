@@ -0,0 +1,125 @@
+package game
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+const (
+	gifCell   = 32
+	gifMargin = 8
+)
+
+// индексы цветов в палитре кадра
+const (
+	gifBackground = iota
+	gifBoard
+	gifGrid
+	gifGreen
+	gifRed
+	gifBlocked
+	gifLegal
+	gifLast
+)
+
+var gifPalette = color.Palette{
+	color.White,
+	hexColor(boardColor),
+	hexColor(gridColor),
+	hexColor(greenColor),
+	hexColor(redColor),
+	hexColor(blockedColor),
+	hexColor(legalColor),
+	hexColor(lastColor),
+}
+
+// WriteGIF анимация из позиций, по кадру на позицию, с паузой delay между кадрами.
+// Последний кадр держится дольше, чтобы был виден итог. Координат на кадрах нет,
+// допустимые ходы отмечены точками, последний ход - жёлтой точкой на фишке.
+func WriteGIF(w io.Writer, views []View, delay time.Duration) error {
+	if len(views) == 0 {
+		return errors.New("no positions")
+	}
+	frames := &gif.GIF{}
+	for i, view := range views {
+		hundredths := int(delay / (10 * time.Millisecond))
+		if i == len(views)-1 {
+			hundredths *= 4
+		}
+		frames.Image = append(frames.Image, gifFrame(view))
+		frames.Delay = append(frames.Delay, hundredths)
+	}
+	return gif.EncodeAll(w, frames)
+}
+
+// WriteGIF анимация всех сыгранных ходов игры
+func (game *Game) WriteGIF(w io.Writer, delay time.Duration) error {
+	return WriteGIF(w, game.Views(), delay)
+}
+
+func gifFrame(view View) *image.Paletted {
+	position := view.Position
+	size := position.Size()
+	side := size*gifCell + 2*gifMargin
+	frame := image.NewPaletted(image.Rect(0, 0, side, side), gifPalette)
+	legal := position.Legal(view.Turn)
+	for number := 0; number < size*size; number++ {
+		x0, y0 := gifMargin+number%size*gifCell, gifMargin+number/size*gifCell
+		fillRect(frame, image.Rect(x0, y0, x0+gifCell, y0+gifCell), gifGrid)
+		inner := image.Rect(x0+1, y0+1, x0+gifCell, y0+gifCell)
+		if position.Blocked().Has(number) {
+			fillRect(frame, inner, gifBlocked)
+			continue
+		}
+		fillRect(frame, inner, gifBoard)
+		cx, cy := x0+gifCell/2, y0+gifCell/2
+		switch {
+		case position.Cell(number) == player.Green:
+			fillCircle(frame, cx, cy, gifCell*2/5, gifGreen)
+		case position.Cell(number) == player.Red:
+			fillCircle(frame, cx, cy, gifCell*2/5, gifRed)
+		case legal.Has(number):
+			fillCircle(frame, cx, cy, gifCell/10, gifLegal)
+		}
+		if view.Last == number {
+			fillCircle(frame, cx, cy, gifCell/8, gifLast)
+		}
+	}
+	// замыкающие линии сетки справа и снизу
+	end := gifMargin + size*gifCell
+	fillRect(frame, image.Rect(end, gifMargin, end+1, end+1), gifGrid)
+	fillRect(frame, image.Rect(gifMargin, end, end+1, end+1), gifGrid)
+	return frame
+}
+
+func fillRect(frame *image.Paletted, rect image.Rectangle, index uint8) {
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			frame.SetColorIndex(x, y, index)
+		}
+	}
+}
+
+// fillCircle круг радиуса r с центром в (cx, cy)
+func fillCircle(frame *image.Paletted, cx, cy, r int, index uint8) {
+	for y := -r; y <= r; y++ {
+		for x := -r; x <= r; x++ {
+			if x*x+y*y <= r*r {
+				frame.SetColorIndex(cx+x, cy+y, index)
+			}
+		}
+	}
+}
+
+// hexColor цвет из записи вида #rrggbb
+func hexColor(s string) color.RGBA {
+	value, _ := strconv.ParseUint(s[1:], 16, 32)
+	return color.RGBA{R: uint8(value >> 16), G: uint8(value >> 8), B: uint8(value), A: 0xff}
+}
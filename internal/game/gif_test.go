@@ -0,0 +1,31 @@
+package game
+
+import (
+	"bytes"
+	"image/gif"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGame_WriteGIF(t *testing.T) {
+	game := New(&mockPlayer{}, &mockPlayer{}, WithSize(4))
+	assert.NoError(t, game.Load("c4b4", -1))
+	var buffer bytes.Buffer
+	assert.NoError(t, game.WriteGIF(&buffer, 500*time.Millisecond))
+
+	result, err := gif.DecodeAll(&buffer)
+	assert.NoError(t, err)
+	assert.Len(t, result.Image, 3)
+	assert.Equal(t, []int{50, 50, 200}, result.Delay)
+	frame := result.Image[2]
+	assert.Equal(t, 4*gifCell+2*gifMargin, frame.Bounds().Dx())
+	assert.Equal(t, gifPalette[gifLast], frame.At(gifMargin+gifCell*3/2, gifMargin+gifCell*7/2), "последний ход B4")
+	assert.Equal(t, gifPalette[gifRed], frame.At(gifMargin+gifCell*3/2+gifCell/4, gifMargin+gifCell*7/2))
+	assert.Equal(t, gifPalette[gifGreen], frame.At(gifMargin+gifCell*5/2, gifMargin+gifCell*7/2), "C4")
+}
+
+func TestWriteGIF_empty(t *testing.T) {
+	assert.EqualError(t, WriteGIF(&bytes.Buffer{}, nil, time.Second), "no positions")
+}
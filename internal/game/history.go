@@ -0,0 +1,153 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// Record ход в истории игры и перевёрнутые им фишки
+type Record struct {
+	Move    Move
+	Flipped Bitboard
+}
+
+// Played ход, сделанный через Play: фишка игрока и перевёрнутые ею фишки,
+// все вместе и по направлениям
+type Played struct {
+	Move    Move
+	Flipped Bitboard
+	Lines   []Line
+}
+
+// History сыгранные ходы, без отменённых
+func (game *Game) History() []Record {
+	return append([]Record(nil), game.history[:game.ply]...)
+}
+
+// Moves сделанные ходы и пропуски по порядку
+func (game *Game) Moves() []Move {
+	result := make([]Move, 0, game.ply)
+	for _, record := range game.history[:game.ply] {
+		result = append(result, record.Move)
+	}
+	return result
+}
+
+// Undo отменяет последний ход или пропуск
+func (game *Game) Undo() error {
+	if err := game.undo(); err != nil {
+		return err
+	}
+	game.log(game.String())
+	return nil
+}
+
+func (game *Game) undo() error {
+	if game.ply == 0 {
+		return errors.New("nothing to undo")
+	}
+	game.ply--
+	record := game.history[game.ply]
+	if !record.Move.IsPass() {
+		own, opp := game.position.bitboards(record.Move.Color)
+		own = own.andNot(bit(record.Move.Cell).or(record.Flipped))
+		opp = opp.or(record.Flipped)
+		game.position = game.position.withBitboards(record.Move.Color, own, opp)
+		game.position.hash ^= moveHash(record.Move.Color, record.Move.Cell, record.Flipped)
+	}
+	game.turn = record.Move.Color
+	game.stepCellN = -1
+	return nil
+}
+
+// Redo повторяет последний отменённый ход
+func (game *Game) Redo() error {
+	if game.ply == len(game.history) {
+		return errors.New("nothing to redo")
+	}
+	record := game.history[game.ply]
+	game.position, _ = game.position.Apply(record.Move)
+	game.ply++
+	game.turn = record.Move.Color.Opponent()
+	game.stepCellN = -1
+	game.log(game.String())
+	return nil
+}
+
+// Replay начинает игру заново и воспроизводит ходы, проверяя каждый.
+// Сыгранными остаются первые ply ходов, остальные можно повторить через Redo.
+// При отрицательном ply сыгранными остаются все ходы.
+func (game *Game) Replay(moves []Move, ply int) error {
+	game.position = game.start
+	game.history = game.history[:0]
+	game.ply = 0
+	game.turn = game.first
+	game.stepCellN = -1
+	for i, move := range moves {
+		if err := game.play(move); err != nil {
+			return fmt.Errorf("move %d %s: %w", i+1, move.Name(game.position.Size()), err)
+		}
+	}
+	for ply >= 0 && game.ply > ply {
+		if err := game.undo(); err != nil {
+			return err
+		}
+	}
+	game.log(game.String())
+	return nil
+}
+
+// play делает ход или пропуск с проверкой очерёдности и допустимости.
+// Вынужденный пропуск (Forced) допустим и при допустимых ходах.
+func (game *Game) play(move Move) error {
+	if move.Color != game.turn {
+		return fmt.Errorf("%s turn", game.turn.Name())
+	}
+	if game.position.IsTerminal() {
+		return errors.New("game is over")
+	}
+	if move.IsPass() {
+		if game.position.HasMoves(move.Color) && !move.Forced {
+			return errors.New("pass with available steps")
+		}
+		game.record(move, Bitboard{})
+		return nil
+	}
+	next, flipped := game.position.Apply(move)
+	if flipped.IsZero() {
+		return errors.New("unavailable step")
+	}
+	game.position = next
+	game.record(move, flipped)
+	return nil
+}
+
+// undoTurn отменяет ходы до последнего хода игрока включительно,
+// чтобы он мог сходить заново
+func (game *Game) undoTurn(color player.Color) error {
+	last := -1
+	for i, record := range game.history[:game.ply] {
+		if record.Move.Color == color && !record.Move.IsPass() {
+			last = i
+		}
+	}
+	if last < 0 {
+		return errors.New("nothing to undo")
+	}
+	for game.ply > last {
+		if err := game.undo(); err != nil {
+			return err
+		}
+	}
+	game.log(game.String())
+	return nil
+}
+
+// record добавляет ход в историю, отменённые ходы при этом забываются
+func (game *Game) record(move Move, flipped Bitboard) {
+	game.history = append(game.history[:game.ply], Record{Move: move, Flipped: flipped})
+	game.ply++
+	game.turn = move.Color.Opponent()
+}
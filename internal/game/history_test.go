@@ -0,0 +1,51 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGame_UndoRedo(t *testing.T) {
+	game := g("")
+	assert.EqualError(t, game.Undo(), "nothing to undo")
+
+	assert.NoError(t, game.Step(Green, "E6"))
+	afterE6 := game.Position()
+	assert.NoError(t, game.Step(Red, "F6"))
+	afterF6 := game.Position()
+	assert.Equal(t, []Record{
+		{Move: Move{Color: Green, Cell: n("E6")}, Flipped: bit(n("E5"))},
+		{Move: Move{Color: Red, Cell: n("F6")}, Flipped: bit(n("E5"))},
+	}, game.History())
+
+	assert.NoError(t, game.Undo())
+	assert.Equal(t, afterE6, game.Position())
+	assert.Equal(t, Red, game.turn)
+	assert.NoError(t, game.Undo())
+	assert.Equal(t, NewPosition(), game.Position())
+	assert.Equal(t, Green, game.turn)
+	assert.Empty(t, game.History())
+
+	assert.NoError(t, game.Redo())
+	assert.NoError(t, game.Redo())
+	assert.EqualError(t, game.Redo(), "nothing to redo")
+	assert.Equal(t, afterF6, game.Position())
+	assert.Equal(t, "[E6 F6]", fmt.Sprint(game.Moves()))
+
+	assert.NoError(t, game.Undo())
+	assert.NoError(t, game.Step(Red, "D6"))
+	assert.EqualError(t, game.Redo(), "nothing to redo")
+	assert.Equal(t, "[E6 D6]", fmt.Sprint(game.Moves()))
+}
+
+func TestGame_Start_undo(t *testing.T) {
+	p1, p2 := &mockPlayer{steps: []string{"C1", "undo", "C1", "H6"}}, &mockPlayer{}
+	game := New(p1, p2)
+	fill(game, "D4:Empty,E4:Empty,D5:Empty,E5:Empty,A1:Green,B1:Red,H8:Green,H7:Red")
+	result := game.Start(context.Background())
+	assert.Equal(t, "[C1 pass H6]", fmt.Sprint(result.Moves))
+	assert.Empty(t, p1.steps)
+}
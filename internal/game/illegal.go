@@ -0,0 +1,104 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// Penalty что делает игра, когда игрок исчерпал попытки сходить
+type Penalty int
+
+const (
+	PenaltyForfeit Penalty = iota // игрок проигрывает
+	PenaltyRandom                 // за игрока делается случайный допустимый ход
+	PenaltyPass                   // игрок пропускает ход, пропуск записывается как вынужденный (Move.Forced)
+)
+
+// ParsePenalty наказание по названию из String
+func ParsePenalty(s string) (Penalty, error) {
+	for _, penalty := range []Penalty{PenaltyForfeit, PenaltyRandom, PenaltyPass} {
+		if s == penalty.String() {
+			return penalty, nil
+		}
+	}
+	return PenaltyForfeit, fmt.Errorf("unknown penalty %q", s)
+}
+
+func (penalty Penalty) String() string {
+	switch penalty {
+	case PenaltyForfeit:
+		return "forfeit"
+	case PenaltyRandom:
+		return "random"
+	case PenaltyPass:
+		return "pass"
+	default:
+		return fmt.Sprintf("undefined(%d)", penalty)
+	}
+}
+
+// IllegalPolicy ограничение недопустимых ходов.
+// Нулевое значение - попытки не ограничены, как раньше.
+type IllegalPolicy struct {
+	MaxAttempts int // недопустимых ходов подряд, после которых игрок наказывается
+	Penalty     Penalty
+	Seed        int64 // для PenaltyRandom
+}
+
+// WithIllegalPolicy наказывает игроков за недопустимые ходы
+func WithIllegalPolicy(policy IllegalPolicy) Option {
+	return func(opts *Options) {
+		opts.illegal = policy
+	}
+}
+
+// Penalized игрок наказан за недопустимые ходы.
+// Для PenaltyRandom и PenaltyPass в Move ход, сделанный за игрока.
+type Penalized struct {
+	Color   player.Color
+	Penalty Penalty
+	Move    Move
+}
+
+func (Penalized) isEvent() {}
+
+var errTooManyAttempts = errors.New("too many illegal attempts")
+
+// violation учитывает недопустимый ход и сообщает, исчерпаны ли попытки
+func (game *Game) violation(attempt IllegalAttempt) bool {
+	game.log("%s player: %s", attempt.Move.Color, attempt.Err)
+	game.illegal = append(game.illegal, attempt)
+	game.attempts++
+	game.emit(attempt)
+	return game.policy.MaxAttempts > 0 && game.attempts >= game.policy.MaxAttempts
+}
+
+// penalize наказывает игрока по политике. Если игра закончена, over = true.
+func (game *Game) penalize(color player.Color) (result Result, over bool) {
+	game.log("%s player: %s, %s", color, errTooManyAttempts, game.policy.Penalty)
+	move := Move{Color: color, Cell: Pass}
+	switch game.policy.Penalty {
+	case PenaltyRandom:
+		if legal := game.position.LegalMoves(color); len(legal) > 0 {
+			if game.random == nil {
+				game.random = rand.New(rand.NewSource(game.policy.Seed))
+			}
+			move = legal[game.random.Intn(len(legal))]
+		}
+	case PenaltyPass:
+		move.Forced = game.position.HasMoves(color) // иначе обычный пропуск
+	default:
+		game.emit(Penalized{Color: color, Penalty: game.policy.Penalty})
+		return game.lose(color, Forfeit), true
+	}
+	if err := game.play(move); err != nil {
+		game.log("%s player: %s", color, err)
+		return game.lose(color, Forfeit), true
+	}
+	game.emit(Penalized{Color: color, Penalty: game.policy.Penalty, Move: move})
+	game.played()
+	return Result{}, false
+}
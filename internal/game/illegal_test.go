@@ -0,0 +1,111 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/slonegd-go/reversi/internal/player"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGame_Start_illegalPolicy(t *testing.T) {
+	a1 := Move{Cell: n("A1")}
+	tests := map[string]struct {
+		green, red  player.Player
+		policy      IllegalPolicy
+		wantReason  Reason
+		wantWinner  player.Color
+		wantIllegal int
+		wantMoves   string
+	}{
+		"forfeit": {
+			green:       &mover{moves: []Move{a1, a1, a1}},
+			red:         &mover{},
+			policy:      IllegalPolicy{MaxAttempts: 2},
+			wantReason:  Forfeit,
+			wantWinner:  player.Red,
+			wantIllegal: 2,
+			wantMoves:   "[]",
+		},
+		"forfeit step player": {
+			green:       &mockPlayer{steps: []string{"A1", "B1", "Z9", "A1", "A1", "A1"}},
+			red:         &mover{},
+			policy:      IllegalPolicy{MaxAttempts: 3},
+			wantReason:  Forfeit,
+			wantWinner:  player.Red,
+			wantIllegal: 3,
+			wantMoves:   "[]",
+		},
+		"random": {
+			green:       &mover{moves: []Move{a1, a1}},
+			red:         &mover{moves: []Move{{Cell: Pass}}},
+			policy:      IllegalPolicy{MaxAttempts: 1, Penalty: PenaltyRandom, Seed: 1},
+			wantReason:  Normal,
+			wantWinner:  player.Green,
+			wantIllegal: 2,
+			wantMoves:   "[H6 pass C1]",
+		},
+		"pass": {
+			green:       &mover{moves: []Move{a1, {Cell: n("C1")}, {Cell: n("H6")}}},
+			red:         &mover{moves: []Move{{Cell: Pass}, {Cell: Pass}}},
+			policy:      IllegalPolicy{MaxAttempts: 1, Penalty: PenaltyPass},
+			wantReason:  Normal,
+			wantWinner:  player.Green,
+			wantIllegal: 1,
+			wantMoves:   "[pass pass C1 pass H6]",
+		},
+	}
+	for name, tt := range tests {
+		penalties := []Penalized{}
+		game := New(tt.green, tt.red, WithIllegalPolicy(tt.policy), WithObserver(func(event Event) {
+			if penalty, ok := event.(Penalized); ok {
+				penalties = append(penalties, penalty)
+			}
+		}))
+		fill(game, passBoard)
+		result := game.Start(context.Background())
+		assert.Equal(t, tt.wantReason, result.Reason, name)
+		assert.Equal(t, tt.wantWinner, result.Winner, name)
+		assert.Len(t, result.Illegal, tt.wantIllegal, name)
+		assert.Equal(t, tt.wantMoves, fmt.Sprint(result.Moves), name)
+		if assert.NotEmpty(t, penalties, name) {
+			assert.Equal(t, tt.policy.Penalty, penalties[0].Penalty, name)
+			assert.Equal(t, player.Green, penalties[0].Color, name)
+		}
+	}
+}
+
+func TestGame_Start_forcedPass(t *testing.T) {
+	a1 := Move{Cell: n("A1")}
+	green := &mover{moves: []Move{a1, {Cell: n("C1")}, {Cell: n("H6")}}}
+	red := &mover{moves: []Move{{Cell: Pass}, {Cell: Pass}}}
+	played := New(green, red, WithIllegalPolicy(IllegalPolicy{MaxAttempts: 1, Penalty: PenaltyPass}))
+	fill(played, passBoard)
+	played.start = played.position // fill меняет только текущую позицию
+	result := played.Start(context.Background())
+	assert.Equal(t, Move{Color: player.Green, Cell: Pass, Forced: true}, result.Moves[0])
+	assert.Equal(t, "pac1h6", played.Transcript())
+
+	reloaded := New(&mover{}, &mover{}, WithPosition(played.Initial()))
+	assert.NoError(t, reloaded.Load(played.Transcript(), -1))
+	assert.Equal(t, result.Moves, reloaded.Moves())
+	assert.Equal(t, played.Position(), reloaded.Position())
+
+	assert.NoError(t, reloaded.Replay(result.Moves, -1))
+	assert.Equal(t, played.Position(), reloaded.Position())
+
+	result.Moves[0].Forced = false
+	assert.EqualError(t, reloaded.Replay(result.Moves, -1), "move 1 pass: pass with available steps")
+}
+
+func TestPenalty_String(t *testing.T) {
+	assert.Equal(t, "forfeit", PenaltyForfeit.String())
+	assert.Equal(t, "random", PenaltyRandom.String())
+	penalty, err := ParsePenalty("pass")
+	assert.NoError(t, err)
+	assert.Equal(t, PenaltyPass, penalty)
+	_, err = ParsePenalty("x")
+	assert.Error(t, err)
+	assert.Equal(t, "pass", PenaltyPass.String())
+}
@@ -0,0 +1,44 @@
+package game
+
+import (
+	"strings"
+
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// Pass номер клетки в ходе, которым игрок пропускает ход
+const Pass = -1
+
+type Move struct {
+	Color  player.Color
+	Cell   int
+	Forced bool // пропуск, сделанный за игрока при допустимых ходах (PenaltyPass)
+}
+
+// IsPass пропуск хода, в том числе вынужденный (Forced)
+func (move Move) IsPass() bool {
+	return move.Cell == Pass
+}
+
+// String название хода на поле 8x8, для других размеров есть Name
+func (move Move) String() string {
+	return move.Name(DefaultSize)
+}
+
+// Name название хода на поле size x size
+func (move Move) Name(size int) string {
+	if move.IsPass() {
+		return "pass"
+	}
+	return cellName(move.Cell, size)
+}
+
+// ParseCell номер клетки на поле size x size по названию, например "E3" или "e3"
+func ParseCell(name string, size int) (int, error) {
+	return parseCellN(strings.ToUpper(name), size)
+}
+
+// CellName название клетки на поле size x size, например "E3"
+func CellName(cellN int, size int) string {
+	return cellName(cellN, size)
+}
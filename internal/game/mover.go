@@ -0,0 +1,142 @@
+package game
+
+import (
+	"context"
+	"errors"
+
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// номера клеток в ходе, которыми игрок не ставит фишку
+const (
+	Resign   = -2 // игрок сдаётся
+	TakeBack = -3 // игрок отменяет свой последний ход, чтобы сходить заново
+)
+
+// Mover игрок второй версии: получает позицию и возвращает ход.
+// Если допустимых ходов нет, игра пропускает ход сама, не спрашивая игрока.
+// Вместо хода можно сдаться (Cell: Resign) или отменить свой ход (Cell: TakeBack).
+// Игрок должен вернуться, когда ctx отменён или кончилось время хода.
+// Цвет хода можно не заполнять, игра подставит цвет игрока.
+type Mover interface {
+	Move(ctx context.Context, position Position, legal []Move, clock Clock) (Move, error)
+	Notify(player.Result)
+	SetColor(player.Color)
+	Color() player.Color
+}
+
+// Interrupter игрок с функцией хода, которого можно прервать.
+// Если игра бросает ход по отмене или концу времени, вызывается Interrupt,
+// после этого Step должен вернуться.
+type Interrupter interface {
+	Interrupt()
+}
+
+// Adapt игрок второй версии из игрока с функцией хода.
+// Игроки, которые уже реализуют Mover, возвращаются как есть.
+func Adapt(p player.Player) Mover {
+	if mover, ok := p.(Mover); ok {
+		return mover
+	}
+	return &adapter{Player: p, busy: make(chan struct{}, 1)}
+}
+
+// adapter вызывает Step в отдельной горутине и ждёт первого допустимого хода.
+// Недопустимые ходы возвращаются игроку ошибкой, как раньше делала игра,
+// и передаются игре как IllegalAttempt, а если игрок вернулся без хода,
+// Step вызывается снова. Брошенный по отмене Step прерывается через Interrupter,
+// а итог и следующий ход игрок получает только после его возврата.
+type adapter struct {
+	player.Player
+	busy chan struct{} // занят, пока идёт Step
+}
+
+func (a *adapter) Move(ctx context.Context, position Position, legal []Move, clock Clock) (Move, error) {
+	color := a.Color()
+	if len(legal) == 0 {
+		return Move{Color: color, Cell: Pass}, nil
+	}
+	if p, ok := a.Player.(ClockPlayer); ok {
+		p.SetClock(clock)
+	}
+
+	size := position.Size()
+	enabled := make([]bool, size*size)
+	for _, move := range legal {
+		enabled[move.Cell] = true
+	}
+	moves := make(chan Move, 1)
+	for {
+		select {
+		case a.busy <- struct{}{}: // брошенный раньше Step мог ещё не вернуться
+		case <-ctx.Done():
+			return Move{}, ctx.Err()
+		}
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			defer func() { <-a.busy }()
+			a.Player.Step(position.Cells(), enabled, func(s string) error {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				move := Move{Color: color, Cell: TakeBack}
+				if s != player.Undo {
+					cellN, err := parseCellN(s, size)
+					switch {
+					case err != nil:
+						cellN = Pass
+					case position.Cell(cellN) != player.Empty:
+						err = errors.New("cell not empty")
+					case !enabled[cellN]:
+						err = errors.New("unavailable step")
+					}
+					if err != nil {
+						reportAttempt(ctx, IllegalAttempt{Move: Move{Color: color, Cell: cellN}, Err: err})
+						return err
+					}
+					move.Cell = cellN
+				}
+				select {
+				case moves <- move:
+					return nil
+				default:
+					return errors.New("move already made")
+				}
+			})
+		}()
+
+		select {
+		case move := <-moves:
+			// игрок может ещё закончить свои дела после хода
+			select {
+			case <-done:
+			case <-ctx.Done():
+				a.interrupt()
+			}
+			return move, nil
+		case <-done:
+			select {
+			case move := <-moves:
+				return move, nil
+			default: // вернулся без хода, спрашиваем снова, как раньше делала игра
+			}
+		case <-ctx.Done():
+			a.interrupt()
+			return Move{}, ctx.Err()
+		}
+	}
+}
+
+// Notify ждёт брошенный Step, чтобы игрок не узнал итог посреди хода
+func (a *adapter) Notify(result player.Result) {
+	a.busy <- struct{}{}
+	defer func() { <-a.busy }()
+	a.Player.Notify(result)
+}
+
+func (a *adapter) interrupt() {
+	if p, ok := a.Player.(Interrupter); ok {
+		p.Interrupt()
+	}
+}
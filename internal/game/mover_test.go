@@ -0,0 +1,165 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/slonegd-go/reversi/internal/player"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGame_Start_mover(t *testing.T) {
+	tests := map[string]struct {
+		green, red  []Move
+		wantMoves   string
+		wantWinner  player.Color
+		wantReason  Reason
+		wantAsked   int // сколько раз спросили зелёного
+		greenResult player.Result
+	}{
+		"moves and pass": {
+			green:       []Move{{Cell: n("C1")}, {Cell: n("H6")}},
+			red:         []Move{{Cell: Pass}},
+			wantMoves:   "[C1 pass H6]",
+			wantWinner:  player.Green,
+			wantReason:  Normal,
+			wantAsked:   2,
+			greenResult: player.Win,
+		},
+		"pass with moves is asked again": {
+			green:       []Move{{Cell: Pass}, {Cell: n("C1")}, {Cell: n("H6")}},
+			red:         []Move{{Cell: Pass}},
+			wantMoves:   "[C1 pass H6]",
+			wantWinner:  player.Green,
+			wantReason:  Normal,
+			wantAsked:   3,
+			greenResult: player.Win,
+		},
+		"resign": {
+			green:       []Move{{Cell: n("C1")}, {Cell: Resign}},
+			red:         []Move{{Cell: Pass}},
+			wantMoves:   "[C1 pass]",
+			wantWinner:  player.Red,
+			wantReason:  Resignation,
+			wantAsked:   2,
+			greenResult: player.Lose,
+		},
+		"error": {
+			green:       []Move{},
+			wantMoves:   "[]",
+			wantWinner:  player.Red,
+			wantReason:  Forfeit,
+			wantAsked:   1,
+			greenResult: player.Lose,
+		},
+	}
+	for name, tt := range tests {
+		green, red := &mover{moves: tt.green}, &mover{moves: tt.red}
+		game := New(green, red)
+		fill(game, passBoard)
+		result := game.Start(context.Background())
+		assert.Equal(t, tt.wantMoves, fmt.Sprint(result.Moves), name)
+		assert.Equal(t, tt.wantWinner, result.Winner, name)
+		assert.Equal(t, tt.wantReason, result.Reason, name)
+		assert.Equal(t, tt.wantAsked, green.asked, name)
+		assert.Equal(t, tt.greenResult, green.result, name)
+	}
+}
+
+func TestGame_Start_cancel(t *testing.T) {
+	green, red := &clockPlayer{delay: time.Hour}, &mockPlayer{result: -1}
+	game := New(green, red)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	result := game.Start(ctx)
+	assert.Equal(t, Aborted, result.Reason)
+	assert.Equal(t, "aborted 2:2", result.String())
+	assert.Equal(t, player.Result(-1), red.result, "not notified")
+}
+
+func TestAdapt(t *testing.T) {
+	old := &mockPlayer{steps: []string{"e6", "D5", "A8", "E6"}}
+	old.SetColor(player.Green)
+	adapted := Adapt(old)
+	position := NewPosition()
+	move, err := adapted.Move(context.Background(), position, position.LegalMoves(player.Green), Clock{})
+	assert.NoError(t, err)
+	assert.Equal(t, Move{Color: player.Green, Cell: n("E6")}, move)
+	assert.Empty(t, old.steps)
+
+	undo := &mockPlayer{steps: []string{player.Undo}}
+	move, err = Adapt(undo).Move(context.Background(), position, position.LegalMoves(player.Green), Clock{})
+	assert.NoError(t, err)
+	assert.Equal(t, TakeBack, move.Cell)
+
+	move, err = Adapt(&mockPlayer{}).Move(context.Background(), position, nil, Clock{})
+	assert.NoError(t, err)
+	assert.True(t, move.IsPass())
+
+	m := &mover{}
+	assert.Equal(t, m, Adapt(m))
+}
+
+func TestAdapt_abandoned(t *testing.T) {
+	slow := &clockPlayer{mockPlayer: mockPlayer{steps: []string{"E6"}}, delay: 50 * time.Millisecond}
+	slow.SetColor(player.Green)
+	adapted := Adapt(slow)
+	position := NewPosition()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := adapted.Move(ctx, position, position.LegalMoves(player.Green), Clock{})
+	assert.Equal(t, context.DeadlineExceeded, err)
+	adapted.Notify(player.Win)
+	assert.Empty(t, slow.steps, "Step returned before Notify")
+	assert.Equal(t, player.Win, slow.result)
+
+	stuck := &stuckPlayer{interrupted: make(chan struct{}, 1)}
+	adapted = Adapt(stuck)
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = adapted.Move(ctx, position, position.LegalMoves(player.Green), Clock{})
+	assert.Equal(t, context.DeadlineExceeded, err)
+	adapted.Notify(player.Lose)
+	assert.Equal(t, player.Lose, stuck.result, "interrupted")
+}
+
+//
+//
+// helpers and mocks
+//
+//
+
+// mover игрок второй версии с заранее заданными ходами
+type mover struct {
+	mockPlayer
+	moves []Move
+	asked int
+}
+
+func (p *mover) Move(_ context.Context, _ Position, _ []Move, _ Clock) (Move, error) {
+	p.asked++
+	if len(p.moves) == 0 {
+		return Move{}, errors.New("no moves")
+	}
+	move := p.moves[0]
+	p.moves = p.moves[1:]
+	return move, nil
+}
+
+// stuckPlayer игрок с функцией хода, который не ходит, пока его не прервут
+type stuckPlayer struct {
+	mockPlayer
+	interrupted chan struct{}
+}
+
+func (p *stuckPlayer) Step([]player.Color, []bool, func(string) error) { <-p.interrupted }
+
+func (p *stuckPlayer) Interrupt() {
+	select {
+	case p.interrupted <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,28 @@
+package game
+
+import "github.com/slonegd-go/reversi/internal/player"
+
+// Perft число листьев дерева игры глубиной depth полуходов из позиции,
+// где ходит turn. Пропуск хода считается полуходом, законченная игра - листом.
+// Числа для начальной позиции опубликованы, поэтому Perft проверяет генерацию ходов.
+func Perft(position Position, turn player.Color, depth int) uint64 {
+	if depth == 0 {
+		return 1
+	}
+	legal := position.Legal(turn)
+	if legal.IsZero() {
+		if !position.HasMoves(turn.Opponent()) {
+			return 1 // игра закончена
+		}
+		return Perft(position, turn.Opponent(), depth-1)
+	}
+	if depth == 1 {
+		return uint64(legal.Count())
+	}
+	var result uint64
+	legal.each(func(cellN int) {
+		next, _ := position.Apply(Move{Color: turn, Cell: cellN})
+		result += Perft(next, turn.Opponent(), depth-1)
+	})
+	return result
+}
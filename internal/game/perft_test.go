@@ -0,0 +1,33 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// опубликованные числа для начальной позиции 8x8
+var perftResults = []uint64{1, 4, 12, 56, 244, 1396, 8200, 55092, 390216, 3005288, 24571284}
+
+func TestPerft(t *testing.T) {
+	depth := 9
+	if !testing.Short() {
+		depth = 10
+	}
+	for d := 0; d <= depth; d++ {
+		assert.Equal(t, perftResults[d], Perft(NewPosition(), Green, d), "depth %d", d)
+	}
+}
+
+func BenchmarkPerft(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Perft(NewPosition(), Green, 7)
+	}
+}
+
+func TestPerft_pass(t *testing.T) {
+	position := g("D4:Empty,E4:Empty,D5:Empty,E5:Empty,A1:Red,B1:Green").position
+	assert.Equal(t, uint64(1), Perft(position, Green, 1), "pass")
+	assert.Equal(t, uint64(1), Perft(position, Green, 2), "red C1")
+	assert.Equal(t, uint64(1), Perft(position, Green, 5), "game over")
+}
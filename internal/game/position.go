@@ -0,0 +1,248 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// Position расположение фишек на поле.
+// Методы не меняют позицию и не имеют побочных эффектов,
+// поэтому её можно копировать и использовать для перебора ходов.
+type Position struct {
+	green    Bitboard
+	red      Bitboard
+	blocked  Bitboard // клетки, в которые нельзя ходить
+	hash     uint64   // см. Hash
+	geometry *geometry
+}
+
+// размеры поля, сторона чётная
+const (
+	MinSize     = 4
+	MaxSize     = 10
+	DefaultSize = 8
+)
+
+func CheckSize(size int) error {
+	if size < MinSize || size > MaxSize || size%2 != 0 {
+		return fmt.Errorf("board size must be even from %d to %d, got: %d", MinSize, MaxSize, size)
+	}
+	return nil
+}
+
+// NewPosition начальная позиция на поле 8x8
+func NewPosition() Position {
+	position, _ := NewPositionSize(DefaultSize)
+	return position
+}
+
+// NewPositionSize начальная позиция на поле size x size, как в отелло:
+// фишки первого хода (зелёные) на E4 и D5 для поля 8x8
+func NewPositionSize(size int) (Position, error) {
+	if err := CheckSize(size); err != nil {
+		return Position{}, err
+	}
+	g := geometries[size]
+	return Position{
+		green:    bit(g.centerTop + 1).or(bit(g.centerTop + size)),
+		red:      bit(g.centerTop).or(bit(g.centerTop + size + 1)),
+		geometry: g,
+	}.rehash(), nil
+}
+
+// PositionFromCells позиция по цветам клеток, как их получают игроки.
+// Размер поля определяется по числу клеток.
+func PositionFromCells(cells []player.Color) (Position, error) {
+	size := 0
+	for size*size < len(cells) {
+		size++
+	}
+	if size*size != len(cells) || CheckSize(size) != nil {
+		return Position{}, fmt.Errorf("position must have square of even size from %d to %d cells, got: %d", MinSize, MaxSize, len(cells))
+	}
+	position := Position{geometry: geometries[size], hash: zobrist.size[size]}
+	for i, color := range cells {
+		position = position.with(i, color)
+	}
+	return position, nil
+}
+
+// Size сторона поля
+func (position Position) Size() int {
+	return position.geometry.size
+}
+
+func (position Position) Cell(cellN int) player.Color {
+	switch {
+	case position.green.Has(cellN):
+		return player.Green
+	case position.red.Has(cellN):
+		return player.Red
+	}
+	return player.Empty
+}
+
+func (position Position) Cells() []player.Color {
+	result := make([]player.Color, position.Size()*position.Size())
+	for i := range result {
+		result[i] = position.Cell(i)
+	}
+	return result
+}
+
+// Discs фишки игрока
+func (position Position) Discs(color player.Color) Bitboard {
+	own, _ := position.bitboards(color)
+	return own
+}
+
+func (position Position) Count(color player.Color) int {
+	return position.Discs(color).Count()
+}
+
+// Empties свободные клетки, без заблокированных
+func (position Position) Empties() Bitboard {
+	return position.geometry.full.andNot(position.green.or(position.red).or(position.blocked))
+}
+
+// Blocked клетки, в которые нельзя ходить. Игрокам они видны как пустые,
+// но недоступные для хода
+func (position Position) Blocked() Bitboard {
+	return position.blocked
+}
+
+// WithBlocked позиция, в которой клетки blocked заблокированы, фишки с них убираются
+func (position Position) WithBlocked(blocked Bitboard) Position {
+	blocked = blocked.and(position.geometry.full)
+	position.green = position.green.andNot(blocked)
+	position.red = position.red.andNot(blocked)
+	position.blocked = blocked
+	return position.rehash()
+}
+
+// Score разница фишек зелёного и красного
+func (position Position) Score() int {
+	return position.green.Count() - position.red.Count()
+}
+
+// Legal клетки, в которые может сходить игрок
+func (position Position) Legal(color player.Color) Bitboard {
+	own, opp := position.bitboards(color)
+	legal := position.geometry.legalMoves(own, opp)
+	if position.blocked.IsZero() {
+		return legal
+	}
+	return legal.andNot(position.blocked)
+}
+
+// LegalMoves допустимые ходы игрока, без пропуска
+func (position Position) LegalMoves(color player.Color) []Move {
+	legal := position.Legal(color)
+	result := make([]Move, 0, legal.Count())
+	for _, cellN := range legal.Cells() {
+		result = append(result, Move{Color: color, Cell: cellN})
+	}
+	return result
+}
+
+func (position Position) HasMoves(color player.Color) bool {
+	return !position.Legal(color).IsZero()
+}
+
+// IsTerminal поле заполнено или ходить не может никто
+func (position Position) IsTerminal() bool {
+	if position.Empties().IsZero() {
+		return true
+	}
+	return !position.HasMoves(player.Green) && !position.HasMoves(player.Red)
+}
+
+// Flips фишки, которые перевернёт ход. Для недопустимого хода и пропуска - 0
+func (position Position) Flips(move Move) Bitboard {
+	if move.IsPass() || !position.Empties().Has(move.Cell) {
+		return Bitboard{}
+	}
+	own, opp := position.bitboards(move.Color)
+	return position.geometry.flips(own, opp, move.Cell)
+}
+
+// Line фишки, перевёрнутые ходом в одном направлении, от ближней к поставленной
+type Line struct {
+	Direction Direction
+	Cells     []int
+}
+
+// FlipLines фишки, которые перевернёт ход, по направлениям в порядке Left, Right,
+// Up, Down, LeftUp, RightUp, LeftDown, RightDown. Направления без переворотов пропускаются.
+func (position Position) FlipLines(move Move) []Line {
+	if move.IsPass() || !position.Empties().Has(move.Cell) {
+		return nil
+	}
+	own, opp := position.bitboards(move.Color)
+	g := position.geometry
+	var result []Line
+	for _, direction := range directionList {
+		flips := g.flipsDirection(own, opp, move.Cell, direction)
+		if flips.IsZero() {
+			continue
+		}
+		line := Line{Direction: direction, Cells: make([]int, 0, flips.Count())}
+		for x := g.shift(bit(move.Cell), direction); !x.and(flips).IsZero(); x = g.shift(x, direction) {
+			line.Cells = append(line.Cells, x.First())
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+// Apply возвращает позицию после хода и перевёрнутые фишки.
+// Недопустимый ход и пропуск оставляют позицию без изменений.
+func (position Position) Apply(move Move) (Position, Bitboard) {
+	flipped := position.Flips(move)
+	if flipped.IsZero() {
+		return position, flipped
+	}
+	own, opp := position.bitboards(move.Color)
+	own = own.or(bit(move.Cell)).or(flipped)
+	opp = opp.andNot(flipped)
+	position.hash ^= moveHash(move.Color, move.Cell, flipped)
+	return position.withBitboards(move.Color, own, opp), flipped
+}
+
+func (position Position) bitboards(color player.Color) (own, opp Bitboard) {
+	if color == player.Red {
+		return position.red, position.green
+	}
+	return position.green, position.red
+}
+
+func (position Position) withBitboards(color player.Color, own, opp Bitboard) Position {
+	if color == player.Red {
+		position.green, position.red = opp, own
+	} else {
+		position.green, position.red = own, opp
+	}
+	return position
+}
+
+// with позиция с изменённой клеткой
+func (position Position) with(cellN int, color player.Color) Position {
+	switch position.Cell(cellN) {
+	case player.Green:
+		position.hash ^= zobrist.green[cellN]
+	case player.Red:
+		position.hash ^= zobrist.red[cellN]
+	}
+	position.green = position.green.andNot(bit(cellN))
+	position.red = position.red.andNot(bit(cellN))
+	switch color {
+	case player.Green:
+		position.green = position.green.or(bit(cellN))
+		position.hash ^= zobrist.green[cellN]
+	case player.Red:
+		position.red = position.red.or(bit(cellN))
+		position.hash ^= zobrist.red[cellN]
+	}
+	return position
+}
@@ -0,0 +1,85 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/slonegd-go/reversi/internal/player"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPosition_LegalMoves(t *testing.T) {
+	start := NewPosition()
+	assert.Equal(t, "[D3 C4 F5 E6]", fmt.Sprint(start.LegalMoves(Green)))
+	assert.Equal(t, "[E3 F4 C5 D6]", fmt.Sprint(start.LegalMoves(Red)))
+}
+
+func TestPosition_Apply(t *testing.T) {
+	tests := map[string]struct {
+		position    Position
+		move        Move
+		wantFlipped []int
+		wantScore   int
+	}{
+		"E6 green":       {position: NewPosition(), move: Move{Color: Green, Cell: n("E6")}, wantFlipped: []int{n("E5")}, wantScore: 3},
+		"D6 green":       {position: NewPosition(), move: Move{Color: Green, Cell: n("D6")}, wantFlipped: []int{}, wantScore: 0},
+		"occupied":       {position: NewPosition(), move: Move{Color: Green, Cell: n("D4")}, wantFlipped: []int{}, wantScore: 0},
+		"pass":           {position: NewPosition(), move: Move{Color: Green, Cell: Pass}, wantFlipped: []int{}, wantScore: 0},
+		"several groups": {position: g("D7:Green,E7:Green,F7:Red,D6:Green").position, move: Move{Color: Red, Cell: n("C7")}, wantFlipped: []int{n("D6"), n("D7"), n("E7")}, wantScore: -5},
+	}
+	for name, tt := range tests {
+		before := tt.position
+		next, flipped := tt.position.Apply(tt.move)
+		assert.Equal(t, before, tt.position, name)
+		assert.Equal(t, tt.wantFlipped, flipped.Cells(), name)
+		assert.Equal(t, tt.wantScore, next.Score(), name)
+		if len(tt.wantFlipped) == 0 {
+			assert.Equal(t, tt.position, next, name)
+		}
+	}
+}
+
+func TestPosition_IsTerminal(t *testing.T) {
+	tests := map[string]struct {
+		position Position
+		want     bool
+	}{
+		"start":         {position: NewPosition(), want: false},
+		"only one side": {position: g("D4:Empty,E4:Empty,D5:Empty,E5:Empty,A1:Green,B1:Red").position, want: false},
+		"blocked":       {position: g("D4:Empty,E4:Empty,D5:Empty,E5:Empty,A1:Green,H8:Red").position, want: true},
+		"full":          {position: Position{green: geometries[8].full, geometry: geometries[8]}, want: true},
+	}
+	for name, tt := range tests {
+		assert.Equal(t, tt.want, tt.position.IsTerminal(), name)
+	}
+}
+
+func TestPosition_Cells(t *testing.T) {
+	cells := NewPosition().Cells()
+	assert.Equal(t, player.Red, cells[n("D4")])
+	assert.Equal(t, player.Green, cells[n("E4")])
+	assert.Equal(t, player.Empty, cells[n("A1")])
+}
+
+func TestNewPositionSize(t *testing.T) {
+	tests := map[int]string{
+		4:  "[B1 A2 D3 C4]",
+		6:  "[C2 B3 E4 D5]",
+		8:  "[D3 C4 F5 E6]",
+		10: "[E4 D5 G6 F7]",
+	}
+	for size, want := range tests {
+		position, err := NewPositionSize(size)
+		assert.NoError(t, err, size)
+		names := []string{}
+		for _, move := range position.LegalMoves(Green) {
+			names = append(names, move.Name(size))
+		}
+		assert.Equal(t, want, fmt.Sprint(names), size)
+		assert.Equal(t, size, position.Size(), size)
+	}
+	for _, size := range []int{2, 7, 12} {
+		_, err := NewPositionSize(size)
+		assert.Error(t, err, size)
+	}
+}
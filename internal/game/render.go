@@ -0,0 +1,146 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// View что показать на поле
+type View struct {
+	Position Position
+	Turn     player.Color // чей ход, для подсветки допустимых ходов
+	Last     int          // клетка последнего хода, -1 если его нет
+	Marker   int          // клетка, в которую пытались сходить, -1 если нет
+}
+
+// Renderer рисует поле текстом
+type Renderer interface {
+	Render(view View) string
+}
+
+// PlainRenderer поле буквами G и R без цвета, годится для логов и тестов.
+// С Highlight точкой отмечены допустимые ходы, а последний ход - знаком >.
+type PlainRenderer struct {
+	Highlight bool
+}
+
+// ANSIRenderer поле цветными буквами G и R в escape-последовательностях ANSI
+type ANSIRenderer struct {
+	Highlight bool
+}
+
+// UnicodeRenderer поле кружками: зелёные ●, красные ○
+type UnicodeRenderer struct {
+	Highlight bool
+}
+
+func (r PlainRenderer) Render(view View) string {
+	return render(view, r.Highlight, func(color player.Color) string {
+		if color == player.Green {
+			return "G"
+		}
+		return "R"
+	}, ".")
+}
+
+const (
+	ansiGreen = "\x1b[32m"
+	ansiRed   = "\x1b[31m"
+	ansiReset = "\x1b[0m"
+)
+
+func (r ANSIRenderer) Render(view View) string {
+	return render(view, r.Highlight, func(color player.Color) string {
+		if color == player.Green {
+			return ansiGreen + "G" + ansiReset
+		}
+		return ansiRed + "R" + ansiReset
+	}, ".")
+}
+
+func (r UnicodeRenderer) Render(view View) string {
+	return render(view, r.Highlight, func(color player.Color) string {
+		if color == player.Green {
+			return "●"
+		}
+		return "○"
+	}, "·")
+}
+
+// DefaultRenderer цветное поле для терминала и простое, если вывод не в терминал
+func DefaultRenderer() Renderer {
+	if color.NoColor {
+		return PlainRenderer{}
+	}
+	return ANSIRenderer{}
+}
+
+// NewRenderer рендерер по названию: plain, ansi или unicode
+func NewRenderer(name string, highlight bool) (Renderer, error) {
+	switch name {
+	case "plain":
+		return PlainRenderer{Highlight: highlight}, nil
+	case "ansi":
+		return ANSIRenderer{Highlight: highlight}, nil
+	case "unicode":
+		return UnicodeRenderer{Highlight: highlight}, nil
+	}
+	return nil, fmt.Errorf("unknown renderer %q, want plain, ansi or unicode", name)
+}
+
+// WithRenderer как рисовать поле в логе и в String
+func WithRenderer(renderer Renderer) Option {
+	return func(opts *Options) {
+		opts.renderer = renderer
+	}
+}
+
+// render общая разметка: заголовок с буквами колонок и по две позиции на клетку
+func render(view View, highlight bool, disc func(player.Color) string, legalMark string) string {
+	position := view.Position
+	size := position.Size()
+	width := len(strconv.Itoa(size)) // ширина номеров строк
+	var legal Bitboard
+	if highlight {
+		legal = position.Legal(view.Turn)
+	}
+	var builder strings.Builder
+	builder.Grow((size + 1) * (2*size + width + 1))
+	builder.WriteString("\n\\")
+	builder.WriteString(strings.Repeat(" ", width-1))
+	for j := 0; j < size; j++ {
+		builder.WriteByte(' ')
+		builder.WriteByte(byte('A' + j))
+	}
+	builder.WriteRune('\n')
+	for i := 0; i < size; i++ {
+		builder.WriteString(fmt.Sprintf("%*d", width, i+1))
+		for j := 0; j < size; j++ {
+			number := i*size + j
+			prefix := " "
+			if highlight && view.Last == number {
+				prefix = ">"
+			}
+			builder.WriteString(prefix)
+			switch {
+			case view.Marker == number:
+				builder.WriteString("x")
+			case position.Blocked().Has(number):
+				builder.WriteString("#")
+			case position.Cell(number) != player.Empty:
+				builder.WriteString(disc(position.Cell(number)))
+			case legal.Has(number):
+				builder.WriteString(legalMark)
+			default:
+				builder.WriteString(" ")
+			}
+		}
+		builder.WriteRune('\n')
+	}
+	return builder.String()
+}
@@ -0,0 +1,61 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderer(t *testing.T) {
+	start, _ := NewPositionSize(4)
+	moved, _ := start.Apply(Move{Color: Green, Cell: 14})
+	moved = moved.WithBlocked(bit(3))
+	tests := map[string]struct {
+		renderer Renderer
+		view     View
+		want     string
+	}{
+		"plain": {
+			renderer: PlainRenderer{},
+			view:     View{Position: start, Turn: Green, Last: -1, Marker: -1},
+			want:     "\n\\ A B C D\n1        \n2   R G  \n3   G R  \n4        \n",
+		},
+		"plain marker and blocked": {
+			renderer: PlainRenderer{},
+			view:     View{Position: moved, Turn: Red, Last: 14, Marker: 12},
+			want:     "\n\\ A B C D\n1       #\n2   R G  \n3   G G  \n4 x   G  \n",
+		},
+		"plain highlight": {
+			renderer: PlainRenderer{Highlight: true},
+			view:     View{Position: moved, Turn: Red, Last: 14, Marker: -1},
+			want:     "\n\\ A B C D\n1       #\n2   R G .\n3   G G  \n4   .>G .\n",
+		},
+		"ansi": {
+			renderer: ANSIRenderer{},
+			view:     View{Position: start, Turn: Green, Last: -1, Marker: -1},
+			want:     "\n\\ A B C D\n1        \n2   \x1b[31mR\x1b[0m \x1b[32mG\x1b[0m  \n3   \x1b[32mG\x1b[0m \x1b[31mR\x1b[0m  \n4        \n",
+		},
+		"unicode highlight": {
+			renderer: UnicodeRenderer{Highlight: true},
+			view:     View{Position: start, Turn: Green, Last: -1, Marker: -1},
+			want:     "\n\\ A B C D\n1   ·    \n2 · ○ ●  \n3   ● ○ ·\n4     ·  \n",
+		},
+	}
+	for name, tt := range tests {
+		assert.Equal(t, tt.want, tt.renderer.Render(tt.view), name)
+	}
+}
+
+func TestNewRenderer(t *testing.T) {
+	renderer, err := NewRenderer("unicode", true)
+	assert.NoError(t, err)
+	assert.Equal(t, UnicodeRenderer{Highlight: true}, renderer)
+	_, err = NewRenderer("html", false)
+	assert.EqualError(t, err, `unknown renderer "html", want plain, ansi or unicode`)
+}
+
+func TestGame_WithRenderer(t *testing.T) {
+	game := New(&mockPlayer{}, &mockPlayer{}, WithSize(4), WithRenderer(PlainRenderer{Highlight: true}))
+	assert.NoError(t, game.Step(Green, "C4"))
+	assert.Equal(t, "\n\\ A B C D\n1        \n2   R G .\n3   G G  \n4   .>G .\n", game.String())
+}
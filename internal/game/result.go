@@ -0,0 +1,62 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// Reason причина окончания игры
+type Reason int
+
+const (
+	Normal      Reason = iota // ходов больше нет
+	Resignation               // игрок сдался
+	Timeout                   // у игрока кончилось время
+	Forfeit                   // игрок наказан поражением за недопустимые ходы
+	Aborted                   // игра остановлена до конца, победителя нет
+)
+
+func (reason Reason) String() string {
+	switch reason {
+	case Normal:
+		return "normal"
+	case Resignation:
+		return "resignation"
+	case Timeout:
+		return "timeout"
+	case Forfeit:
+		return "forfeit"
+	case Aborted:
+		return "aborted"
+	default:
+		return fmt.Sprintf("undefined(%d)", reason)
+	}
+}
+
+type Result struct {
+	Winner     player.Color // player.Empty при ничьей
+	Green, Red int          // фишек на поле в конце игры
+	Reason     Reason
+	Moves      []Move
+	Size       int              // сторона поля
+	Illegal    []IllegalAttempt // недопустимые ходы игроков по порядку
+}
+
+func (result Result) Draw() bool {
+	return result.Winner == player.Empty
+}
+
+func (result Result) String() string {
+	if result.Reason == Aborted {
+		return fmt.Sprintf("aborted %d:%d", result.Green, result.Red)
+	}
+	if result.Draw() {
+		return fmt.Sprintf("draw %d:%d", result.Green, result.Red)
+	}
+	s := fmt.Sprintf("%s player win %d:%d", result.Winner, result.Green, result.Red)
+	if result.Reason != Normal {
+		s += fmt.Sprintf(" by %s", result.Reason)
+	}
+	return s
+}
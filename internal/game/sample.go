@@ -0,0 +1,28 @@
+package game
+
+import "github.com/slonegd-go/reversi/internal/player"
+
+// Sample позиция, сделанный в ней ход и итог партии - пример для обучения игроков
+type Sample struct {
+	Position Position
+	Move     Move
+	Score    int // итоговая разница фишек в пользу сделавшего ход
+}
+
+// Samples примеры по всем ходам партии, пропуски не попадают.
+// score итоговая разница фишек в пользу зелёного.
+func Samples(start Position, moves []Move, score int) []Sample {
+	result := make([]Sample, 0, len(moves))
+	position := start
+	for _, move := range moves {
+		if !move.IsPass() {
+			sample := Sample{Position: position, Move: move, Score: score}
+			if move.Color != player.Green {
+				sample.Score = -score
+			}
+			result = append(result, sample)
+		}
+		position, _ = position.Apply(move)
+	}
+	return result
+}
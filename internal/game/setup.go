@@ -0,0 +1,116 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// Запись позиции: по символу на клетку с A1 по строкам и, через пробел,
+// чей ход. Пробелы и переводы строк внутри поля допускаются, размер поля
+// определяется по числу клеток. Начальная позиция 8x8:
+//
+//	-------- -------- -------- ---GR--- ---RG--- -------- -------- -------- G
+const (
+	setupEmpty   = '-'
+	setupGreen   = 'G'
+	setupRed     = 'R'
+	setupBlocked = '#'
+)
+
+// ParseSetup позиция и чей ход по записи
+func ParseSetup(setup string) (Position, player.Color, error) {
+	fields := strings.Fields(setup)
+	if len(fields) < 2 {
+		return Position{}, player.Empty, errors.New("setup must have cells and side to move")
+	}
+	board := strings.Join(fields[:len(fields)-1], "")
+	cells := make([]player.Color, 0, len(board))
+	var blocked []int
+	for i, char := range board {
+		switch char {
+		case setupEmpty:
+			cells = append(cells, player.Empty)
+		case setupGreen:
+			cells = append(cells, player.Green)
+		case setupRed:
+			cells = append(cells, player.Red)
+		case setupBlocked:
+			cells = append(cells, player.Empty)
+			blocked = append(blocked, len(cells)-1)
+		default:
+			return Position{}, player.Empty, fmt.Errorf("unknown cell %q at %d", char, i+1)
+		}
+	}
+	position, err := PositionFromCells(cells)
+	if err != nil {
+		return Position{}, player.Empty, err
+	}
+	var mask Bitboard
+	for _, cellN := range blocked {
+		mask = mask.or(bit(cellN))
+	}
+	position = position.WithBlocked(mask)
+
+	var turn player.Color
+	switch fields[len(fields)-1] {
+	case string(setupGreen):
+		turn = player.Green
+	case string(setupRed):
+		turn = player.Red
+	default:
+		return Position{}, player.Empty, fmt.Errorf("side to move must be %c or %c, got: %s", setupGreen, setupRed, fields[len(fields)-1])
+	}
+	return position, turn, nil
+}
+
+// FormatSetup запись позиции, строки поля разделены пробелами
+func FormatSetup(position Position, turn player.Color) string {
+	size := position.Size()
+	var builder strings.Builder
+	builder.Grow(size*(size+1) + 1)
+	for cellN := 0; cellN < size*size; cellN++ {
+		if cellN > 0 && cellN%size == 0 {
+			builder.WriteByte(' ')
+		}
+		switch {
+		case position.Blocked().Has(cellN):
+			builder.WriteByte(setupBlocked)
+		case position.Cell(cellN) == player.Green:
+			builder.WriteByte(setupGreen)
+		case position.Cell(cellN) == player.Red:
+			builder.WriteByte(setupRed)
+		default:
+			builder.WriteByte(setupEmpty)
+		}
+	}
+	builder.WriteByte(' ')
+	if turn == player.Red {
+		builder.WriteByte(setupRed)
+	} else {
+		builder.WriteByte(setupGreen)
+	}
+	return builder.String()
+}
+
+// WithSetup начинает игру с позиции в записи, см. ParseSetup.
+// Запись можно проверить заранее через ParseSetup,
+// ошибочная пишется в лог и не применяется.
+func WithSetup(setup string) Option {
+	return func(opts *Options) {
+		position, turn, err := ParseSetup(setup)
+		if err != nil {
+			opts.fail(err)
+			return
+		}
+		opts.position = position
+		opts.turn = turn
+	}
+}
+
+// Setup запись текущей позиции и чей ход
+func (game *Game) Setup() string {
+	return FormatSetup(game.position, game.turn)
+}
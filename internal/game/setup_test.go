@@ -0,0 +1,72 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/slonegd-go/reversi/internal/player"
+	"github.com/stretchr/testify/assert"
+)
+
+const startSetup = "-------- -------- -------- ---RG--- ---GR--- -------- -------- -------- G"
+
+func TestParseSetup(t *testing.T) {
+	position, turn, err := ParseSetup(startSetup)
+	assert.NoError(t, err)
+	assert.Equal(t, NewPosition(), position)
+	assert.Equal(t, player.Green, turn)
+
+	position, turn, err = ParseSetup("#---\n-GR-\n-RG-\n---R R")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, position.Size())
+	assert.Equal(t, player.Red, turn)
+	assert.Equal(t, []int{0}, position.Blocked().Cells())
+	assert.Equal(t, 3, position.Count(player.Red))
+}
+
+func TestParseSetup_errors(t *testing.T) {
+	tests := map[string]struct {
+		setup   string
+		wantErr string
+	}{
+		"empty":        {setup: "", wantErr: "setup must have cells and side to move"},
+		"no side":      {setup: "----------------", wantErr: "setup must have cells and side to move"},
+		"unknown cell": {setup: "---x------------ G", wantErr: "unknown cell 'x' at 4"},
+		"size":         {setup: "------------ G", wantErr: "position must have square of even size from 4 to 10 cells, got: 12"},
+		"side":         {setup: "---------------- -", wantErr: "side to move must be G or R, got: -"},
+	}
+	for name, tt := range tests {
+		_, _, err := ParseSetup(tt.setup)
+		assert.EqualError(t, err, tt.wantErr, name)
+	}
+}
+
+func TestFormatSetup_roundTrip(t *testing.T) {
+	setups := []string{
+		startSetup,
+		"#--- -GR- -RG- ---R R",
+		"GGGGGG RRRRRR ------ ------ --#--- ------ G",
+	}
+	for _, setup := range setups {
+		position, turn, err := ParseSetup(setup)
+		if assert.NoError(t, err, setup) {
+			assert.Equal(t, setup, FormatSetup(position, turn))
+		}
+	}
+}
+
+func TestGame_WithSetup(t *testing.T) {
+	game := New(&mockPlayer{}, &mockPlayer{}, WithSetup("-------- -------- -------- ---GR--- ---GG--- ----G--- -------- -------- R"))
+	assert.Equal(t, "[C4 C6 E7]", fmt.Sprint(game.Position().LegalMoves(player.Red)))
+	assert.EqualError(t, game.Step(player.Red, "F4"), "unavailable step")
+	assert.NoError(t, game.Step(player.Red, "E7"))
+	assert.Equal(t, "-------- -------- -------- ---GR--- ---GR--- ----R--- ----R--- -------- G", game.Setup())
+}
+
+func TestGame_WithSetup_invalid(t *testing.T) {
+	logs := []string{}
+	game := New(&mockPlayer{}, &mockPlayer{}, WithSetup("---- G"), WithSize(3),
+		WithLogger(func(format string, args ...interface{}) { logs = append(logs, fmt.Sprintf(format, args...)) }))
+	assert.Equal(t, NewPosition(), game.Position())
+	assert.Contains(t, logs[0], "options: ")
+}
@@ -0,0 +1,96 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// цвета диаграмм, общие для SVG и GIF
+const (
+	boardColor   = "#c8b68a"
+	gridColor    = "#5a4a2a"
+	greenColor   = "#2e9e3e"
+	redColor     = "#d03030"
+	blockedColor = "#404040"
+	legalColor   = "#5a4a2a"
+	lastColor    = "#ffd700"
+)
+
+// SVGRenderer диаграмма позиции в SVG с буквами колонок и номерами строк.
+// С Highlight точками отмечены допустимые ходы, а последний ход - жёлтой точкой на фишке.
+type SVGRenderer struct {
+	Highlight bool
+}
+
+const (
+	svgCell   = 40 // сторона клетки
+	svgMargin = 24 // поле под координаты
+)
+
+func (r SVGRenderer) Render(view View) string {
+	position := view.Position
+	size := position.Size()
+	board := size * svgCell
+	side := board + 2*svgMargin
+	var legal Bitboard
+	if r.Highlight {
+		legal = position.Legal(view.Turn)
+	}
+	center := func(i int) int { return svgMargin + i*svgCell + svgCell/2 }
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", side, side, side, side)
+	fmt.Fprintf(&builder, `<rect width="%d" height="%d" fill="white"/>`+"\n", side, side)
+	fmt.Fprintf(&builder, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", svgMargin, svgMargin, board, board, boardColor)
+	for i := 0; i <= size; i++ {
+		offset := svgMargin + i*svgCell
+		fmt.Fprintf(&builder, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s"/>`+"\n", svgMargin, offset, svgMargin+board, offset, gridColor)
+		fmt.Fprintf(&builder, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s"/>`+"\n", offset, svgMargin, offset, svgMargin+board, gridColor)
+	}
+	builder.WriteString(`<g font-family="sans-serif" font-size="14" text-anchor="middle" dominant-baseline="central">` + "\n")
+	for i := 0; i < size; i++ {
+		fmt.Fprintf(&builder, `<text x="%d" y="%d">%c</text>`+"\n", center(i), svgMargin/2, 'A'+i)
+		fmt.Fprintf(&builder, `<text x="%d" y="%d">%d</text>`+"\n", svgMargin/2, center(i), i+1)
+	}
+	builder.WriteString("</g>\n")
+
+	for number := 0; number < size*size; number++ {
+		x, y := center(number%size), center(number/size)
+		switch {
+		case position.Blocked().Has(number):
+			fmt.Fprintf(&builder, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", x-svgCell/2, y-svgCell/2, svgCell, svgCell, blockedColor)
+		case position.Cell(number) != player.Empty:
+			fill := greenColor
+			if position.Cell(number) == player.Red {
+				fill = redColor
+			}
+			fmt.Fprintf(&builder, `<circle cx="%d" cy="%d" r="%d" fill="%s"/>`+"\n", x, y, svgCell*2/5, fill)
+			if r.Highlight && view.Last == number {
+				fmt.Fprintf(&builder, `<circle cx="%d" cy="%d" r="%d" fill="%s"/>`+"\n", x, y, svgCell/8, lastColor)
+			}
+		case legal.Has(number):
+			fmt.Fprintf(&builder, `<circle cx="%d" cy="%d" r="%d" fill="%s"/>`+"\n", x, y, svgCell/10, legalColor)
+		}
+		if view.Marker == number {
+			d := svgCell / 4
+			fmt.Fprintf(&builder, `<path d="M%d %dL%d %dM%d %dL%d %d" stroke="%s" stroke-width="3"/>`+"\n", x-d, y-d, x+d, y+d, x-d, y+d, x+d, y-d, redColor)
+		}
+	}
+	builder.WriteString("</svg>\n")
+	return builder.String()
+}
+
+// Views позиции игры от начальной после каждого сыгранного хода,
+// например чтобы нарисовать любую из них или всю игру
+func (game *Game) Views() []View {
+	position := game.start
+	views := make([]View, 0, game.ply+1)
+	views = append(views, View{Position: position, Turn: game.first, Last: -1, Marker: -1})
+	for _, record := range game.history[:game.ply] {
+		position, _ = position.Apply(record.Move)
+		views = append(views, View{Position: position, Turn: record.Move.Color.Opponent(), Last: record.Move.Cell, Marker: -1})
+	}
+	return views
+}
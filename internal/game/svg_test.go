@@ -0,0 +1,35 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSVGRenderer(t *testing.T) {
+	game := New(&mockPlayer{}, &mockPlayer{}, WithSize(4))
+	assert.NoError(t, game.Step(Green, "C4"))
+	view := game.View()
+
+	svg := SVGRenderer{}.Render(view)
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="208" height="208"`))
+	assert.Contains(t, svg, `<text x="164" y="12">D</text>`)
+	assert.Contains(t, svg, `<text x="12" y="164">4</text>`)
+	assert.Equal(t, 5, strings.Count(svg, "<circle"), "только фишки")
+
+	svg = SVGRenderer{Highlight: true}.Render(view)
+	assert.Equal(t, 5+3+1, strings.Count(svg, "<circle"), "фишки, допустимые ходы и последний ход")
+	assert.Contains(t, svg, `<circle cx="124" cy="164" r="5" fill="#ffd700"/>`)
+}
+
+func TestGame_Views(t *testing.T) {
+	game := New(&mockPlayer{}, &mockPlayer{}, WithSize(4))
+	assert.NoError(t, game.Load("c4b4", -1))
+	views := game.Views()
+	assert.Len(t, views, 3)
+	assert.Equal(t, View{Position: game.start, Turn: Green, Last: -1, Marker: -1}, views[0])
+	assert.Equal(t, 14, views[1].Last)
+	assert.Equal(t, Red, views[1].Turn)
+	assert.Equal(t, View{Position: game.Position(), Turn: Green, Last: 13, Marker: -1}, views[2])
+}
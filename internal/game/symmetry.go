@@ -0,0 +1,138 @@
+package game
+
+import "fmt"
+
+// Symmetry одно из 8 преобразований квадратного поля, сохраняющих правила
+type Symmetry int
+
+const (
+	Identity         Symmetry = iota
+	Rotate90                  // по часовой стрелке
+	Rotate180                 //
+	Rotate270                 //
+	FlipHorizontal            // отражение слева направо
+	FlipVertical              // отражение сверху вниз
+	FlipDiagonal              // отражение относительно диагонали A1-H8
+	FlipAntiDiagonal          // отражение относительно диагонали H1-A8
+)
+
+// Symmetries все преобразования по порядку
+var Symmetries = []Symmetry{Identity, Rotate90, Rotate180, Rotate270, FlipHorizontal, FlipVertical, FlipDiagonal, FlipAntiDiagonal}
+
+func (symmetry Symmetry) String() string {
+	switch symmetry {
+	case Identity:
+		return "identity"
+	case Rotate90:
+		return "rotate 90"
+	case Rotate180:
+		return "rotate 180"
+	case Rotate270:
+		return "rotate 270"
+	case FlipHorizontal:
+		return "flip horizontal"
+	case FlipVertical:
+		return "flip vertical"
+	case FlipDiagonal:
+		return "flip diagonal"
+	case FlipAntiDiagonal:
+		return "flip anti-diagonal"
+	default:
+		return fmt.Sprintf("undefined(%d)", symmetry)
+	}
+}
+
+// Inverse преобразование, возвращающее поле обратно
+func (symmetry Symmetry) Inverse() Symmetry {
+	switch symmetry {
+	case Rotate90:
+		return Rotate270
+	case Rotate270:
+		return Rotate90
+	}
+	return symmetry
+}
+
+// Cell куда переходит клетка cellN поля size x size
+func (symmetry Symmetry) Cell(cellN int, size int) int {
+	row, column := cellN/size, cellN%size
+	last := size - 1
+	switch symmetry {
+	case Rotate90:
+		row, column = column, last-row
+	case Rotate180:
+		row, column = last-row, last-column
+	case Rotate270:
+		row, column = last-column, row
+	case FlipHorizontal:
+		column = last - column
+	case FlipVertical:
+		row = last - row
+	case FlipDiagonal:
+		row, column = column, row
+	case FlipAntiDiagonal:
+		row, column = last-column, last-row
+	}
+	return row*size + column
+}
+
+// Move ход, преобразованный вместе с полем size x size. Пропуск не меняется
+func (symmetry Symmetry) Move(move Move, size int) Move {
+	if !move.IsPass() {
+		move.Cell = symmetry.Cell(move.Cell, size)
+	}
+	return move
+}
+
+// Transform позиция, преобразованная symmetry
+func (position Position) Transform(symmetry Symmetry) Position {
+	if symmetry == Identity {
+		return position
+	}
+	size := position.Size()
+	transform := func(b Bitboard) Bitboard {
+		var result Bitboard
+		b.each(func(cellN int) {
+			result = result.or(bit(symmetry.Cell(cellN, size)))
+		})
+		return result
+	}
+	position.green = transform(position.green)
+	position.red = transform(position.red)
+	position.blocked = transform(position.blocked)
+	return position.rehash()
+}
+
+// Canonical представитель позиции среди всех её преобразований
+// и преобразование, которое к нему приводит. Равные с точностью до симметрии
+// позиции имеют один и тот же представитель, поэтому его хеш годится
+// для книг дебютов и поиска в базах партий. Ход из книги для исходной позиции
+// получается через symmetry.Inverse().Move.
+func (position Position) Canonical() (Position, Symmetry) {
+	best, bestSymmetry := position, Identity
+	for _, symmetry := range Symmetries[1:] {
+		candidate := position.Transform(symmetry)
+		if candidate.less(best) {
+			best, bestSymmetry = candidate, symmetry
+		}
+	}
+	return best, bestSymmetry
+}
+
+// less порядок позиций одного размера по битовым маскам
+func (position Position) less(other Position) bool {
+	pairs := [][2]uint64{
+		{position.green.hi, other.green.hi},
+		{position.green.lo, other.green.lo},
+		{position.red.hi, other.red.hi},
+		{position.red.lo, other.red.lo},
+		{position.blocked.hi, other.blocked.hi},
+		{position.blocked.lo, other.blocked.lo},
+	}
+	for _, pair := range pairs {
+		if pair[0] != pair[1] {
+			return pair[0] < pair[1]
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package game
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/slonegd-go/reversi/internal/player"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymmetry_Cell(t *testing.T) {
+	tests := map[Symmetry]string{
+		Identity:         "B1",
+		Rotate90:         "H2",
+		Rotate180:        "G8",
+		Rotate270:        "A7",
+		FlipHorizontal:   "G1",
+		FlipVertical:     "B8",
+		FlipDiagonal:     "A2",
+		FlipAntiDiagonal: "H7",
+	}
+	for symmetry, want := range tests {
+		assert.Equal(t, want, cellName(symmetry.Cell(n("B1"), 8), 8), symmetry.String())
+		for cellN := 0; cellN < 36; cellN++ {
+			assert.Equal(t, cellN, symmetry.Inverse().Cell(symmetry.Cell(cellN, 6), 6), symmetry.String())
+		}
+	}
+}
+
+func TestPosition_Transform(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	moves := randomGame(rnd)[:20]
+	position := NewPosition()
+	for _, move := range moves {
+		position, _ = position.Apply(move)
+	}
+	for _, symmetry := range Symmetries {
+		transformed := position.Transform(symmetry)
+		assert.Equal(t, transformed.rehash().Hash(), transformed.Hash(), symmetry.String())
+		assert.Equal(t, position.Transform(symmetry).Transform(symmetry.Inverse()), position, symmetry.String())
+		for _, color := range []player.Color{Green, Red} {
+			want := []int{}
+			for _, move := range position.LegalMoves(color) {
+				want = append(want, symmetry.Move(move, 8).Cell)
+			}
+			sort.Ints(want)
+			assert.Equal(t, want, transformed.Legal(color).Cells(), symmetry.String())
+		}
+	}
+}
+
+func TestPosition_Canonical(t *testing.T) {
+	// четыре первых хода равны с точностью до симметрии
+	start := NewPosition()
+	hashes := map[uint64]bool{}
+	for _, move := range start.LegalMoves(Green) {
+		position, _ := start.Apply(move)
+		canonical, symmetry := position.Canonical()
+		assert.Equal(t, canonical, position.Transform(symmetry))
+		hashes[canonical.Hash()] = true
+	}
+	assert.Len(t, hashes, 1)
+
+	rnd := rand.New(rand.NewSource(2))
+	position := start
+	for _, move := range randomGame(rnd)[:15] {
+		position, _ = position.Apply(move)
+	}
+	want, _ := position.Canonical()
+	for _, symmetry := range Symmetries {
+		got, _ := position.Transform(symmetry).Canonical()
+		assert.Equal(t, want, got, symmetry.String())
+	}
+}
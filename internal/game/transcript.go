@@ -0,0 +1,123 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// FormatTranscript запись партии на поле size x size в общепринятой нотации:
+// клетки ходов строчными буквами подряд, например "f5d6c3". Пропуски не пишутся,
+// они однозначно восстанавливаются при чтении. Вынужденный пропуск (Move.Forced)
+// восстановить нельзя, он пишется как "pa".
+func FormatTranscript(moves []Move, size int) string {
+	var builder strings.Builder
+	builder.Grow(len(moves) * 2)
+	for _, move := range moves {
+		if move.Forced {
+			builder.WriteString(forcedPass)
+			continue
+		}
+		if move.IsPass() {
+			continue
+		}
+		builder.WriteString(strings.ToLower(cellName(move.Cell, size)))
+	}
+	return builder.String()
+}
+
+// ParseTranscript разбирает запись партии с начальной позиции поля size x size,
+// проверяя каждый ход. В результат добавляются пропуски ходов там, где они были.
+func ParseTranscript(transcript string, size int) ([]Move, error) {
+	position, err := NewPositionSize(size)
+	if err != nil {
+		return nil, err
+	}
+	return parseTranscript(position, player.Green, transcript)
+}
+
+func parseTranscript(position Position, color player.Color, transcript string) ([]Move, error) {
+	cells := splitTranscript(strings.TrimSpace(transcript))
+	result := make([]Move, 0, len(cells))
+	for i, cell := range cells {
+		forced := strings.EqualFold(cell, forcedPass)
+		cellN := Pass
+		if !forced {
+			var err error
+			cellN, err = parseCellN(strings.ToUpper(cell), position.Size())
+			if err != nil {
+				return nil, fmt.Errorf("move %d: %w", i+1, err)
+			}
+		}
+		if position.IsTerminal() {
+			return nil, fmt.Errorf("move %d %s: game is over", i+1, cell)
+		}
+		if !position.HasMoves(color) {
+			result = append(result, Move{Color: color, Cell: Pass})
+			color = color.Opponent()
+		}
+		if forced {
+			result = append(result, Move{Color: color, Cell: Pass, Forced: true})
+			color = color.Opponent()
+			continue
+		}
+		move := Move{Color: color, Cell: cellN}
+		next, flipped := position.Apply(move)
+		if flipped.IsZero() {
+			return nil, fmt.Errorf("move %d %s: unavailable step for %s", i+1, cell, color.Name())
+		}
+		position = next
+		result = append(result, move)
+		color = color.Opponent()
+	}
+	return result, nil
+}
+
+// forcedPass вынужденный пропуск в записи, столбца P на полях до MaxSize нет
+const forcedPass = "pa"
+
+// splitTranscript делит запись на клетки: буква и следующие за ней цифры,
+// и вынужденные пропуски
+func splitTranscript(transcript string) []string {
+	result := []string{}
+	for start := 0; start < len(transcript); {
+		end := start + 1
+		if strings.HasPrefix(strings.ToLower(transcript[start:]), forcedPass) {
+			end = start + len(forcedPass)
+		}
+		for end < len(transcript) && transcript[end] >= '0' && transcript[end] <= '9' {
+			end++
+		}
+		result = append(result, transcript[start:end])
+		start = end
+	}
+	return result
+}
+
+// Transcript запись сыгранных ходов
+func (game *Game) Transcript() string {
+	return FormatTranscript(game.Moves(), game.position.Size())
+}
+
+// Load начинает игру заново и воспроизводит запись партии.
+// Сыгранными остаются первые ply ходов записи, остальные можно повторить через Redo.
+// При отрицательном ply сыгранными остаются все ходы.
+func (game *Game) Load(transcript string, ply int) error {
+	moves, err := parseTranscript(game.start, game.first, transcript)
+	if err != nil {
+		return err
+	}
+
+	// пропуски в записи не считаются
+	records := len(moves)
+	if ply >= 0 {
+		records = 0
+		for played := 0; records < len(moves) && played < ply; records++ {
+			if !moves[records].IsPass() {
+				played++
+			}
+		}
+	}
+	return game.Replay(moves, records)
+}
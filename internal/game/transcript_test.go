@@ -0,0 +1,101 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTranscript(t *testing.T) {
+	tests := map[string]struct {
+		transcript string
+		want       string
+		wantErr    string
+	}{
+		"empty":       {transcript: "", want: "[]"},
+		"tiger":       {transcript: "f5d6c3d3c4", want: "[F5 D6 C3 D3 C4]"},
+		"parallel":    {transcript: "f5f4", want: "[F5 F4]"},
+		"upper case":  {transcript: "F5D6", want: "[F5 D6]"},
+		"no line":     {transcript: "f5f", wantErr: "move 2: position must be from A1 to H8, got: F"},
+		"bad cell":    {transcript: "f5z9", wantErr: "move 2: position must be from A1 to H8, got: Z9"},
+		"unavailable": {transcript: "e3", wantErr: "move 1 e3: unavailable step for green"},
+	}
+	for name, tt := range tests {
+		got, err := ParseTranscript(tt.transcript, 8)
+		if tt.wantErr != "" {
+			assert.EqualError(t, err, tt.wantErr, name)
+			continue
+		}
+		assert.NoError(t, err, name)
+		assert.Equal(t, tt.want, fmt.Sprint(got), name)
+	}
+}
+
+func TestFormatTranscript(t *testing.T) {
+	game := g("")
+	for _, position := range []string{"F5", "D6", "C3", "D3", "C4"} {
+		assert.NoError(t, game.Step(game.turn, position))
+	}
+	assert.Equal(t, "f5d6c3d3c4", game.Transcript())
+
+	moves, err := ParseTranscript("f5d6c3d3c4", 8)
+	assert.NoError(t, err)
+	assert.Equal(t, game.Moves(), moves)
+}
+
+func TestTranscript_roundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		moves := randomGame(rnd)
+		transcript := FormatTranscript(moves, 8)
+		got, err := ParseTranscript(transcript, 8)
+		assert.NoError(t, err, transcript)
+		assert.Equal(t, moves, got, transcript)
+	}
+}
+
+func TestGame_Load(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	moves := randomGame(rnd)
+	transcript := FormatTranscript(moves, 8)
+
+	game := g("")
+	assert.NoError(t, game.Load(transcript, -1))
+	assert.Equal(t, moves, game.Moves())
+	assert.True(t, game.Position().IsTerminal())
+	final := game.Position()
+
+	assert.NoError(t, game.Load(transcript, 3))
+	assert.Equal(t, transcript[:6], game.Transcript())
+	for game.Redo() == nil {
+	}
+	assert.Equal(t, final, game.Position())
+	assert.Equal(t, transcript, game.Transcript())
+
+	assert.Error(t, game.Load("f5f5", -1))
+}
+
+//
+//
+// helpers and mocks
+//
+//
+
+func randomGame(rnd *rand.Rand) []Move {
+	position := NewPosition()
+	color := Green
+	moves := []Move{}
+	for !position.IsTerminal() {
+		legal := position.LegalMoves(color)
+		move := Move{Color: color, Cell: Pass}
+		if len(legal) != 0 {
+			move = legal[rnd.Intn(len(legal))]
+		}
+		position, _ = position.Apply(move)
+		moves = append(moves, move)
+		color = color.Opponent()
+	}
+	return moves
+}
@@ -0,0 +1,125 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// Variant правила, отличающиеся от обычной игры.
+// Нулевое значение - обычные правила.
+type Variant struct {
+	Anti        bool     // анти-реверси: побеждает тот, у кого меньше фишек
+	RandomMoves int      // столько случайных допустимых ходов делается перед началом игры
+	Seed        int64    // для случайных ходов
+	Blocked     []string // клетки, в которые нельзя ходить всю игру
+}
+
+// VariantPlayer игрок, которому нужно знать правила.
+// Игра сообщает их при создании, до первого хода.
+type VariantPlayer interface {
+	SetVariant(Variant)
+}
+
+func (variant Variant) String() string {
+	parts := []string{}
+	if variant.Anti {
+		parts = append(parts, "anti")
+	}
+	if variant.RandomMoves > 0 {
+		parts = append(parts, fmt.Sprintf("random %d moves (seed %d)", variant.RandomMoves, variant.Seed))
+	}
+	if len(variant.Blocked) > 0 {
+		parts = append(parts, "blocked "+strings.Join(variant.Blocked, " "))
+	}
+	if len(parts) == 0 {
+		return "standard"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// WithVariant игра по правилам variant
+func WithVariant(variant Variant) Option {
+	return func(opts *Options) {
+		opts.variant = variant
+	}
+}
+
+// WithAnti игра в анти-реверси
+func WithAnti() Option {
+	return func(opts *Options) {
+		opts.variant.Anti = true
+	}
+}
+
+// WithRandomStart игра со случайной позиции после moves случайных ходов
+func WithRandomStart(moves int, seed int64) Option {
+	return func(opts *Options) {
+		opts.variant.RandomMoves = moves
+		opts.variant.Seed = seed
+	}
+}
+
+// WithBlocked клетки, в которые нельзя ходить, например "A1", "H8".
+// Клетки должны быть пустыми в начальной позиции.
+func WithBlocked(cells ...string) Option {
+	return func(opts *Options) {
+		opts.variant.Blocked = append(opts.variant.Blocked, cells...)
+	}
+}
+
+// Check проверяет закрытые клетки: они должны быть на поле position
+// и пустыми в ней. Игра с ошибочными закрытыми клетками идёт без них.
+func (variant Variant) Check(position Position) error {
+	_, err := variant.blocked(position)
+	return err
+}
+
+// blocked закрытые клетки варианта на поле position
+func (variant Variant) blocked(position Position) (Bitboard, error) {
+	var blocked Bitboard
+	for _, name := range variant.Blocked {
+		cellN, err := ParseCell(name, position.Size())
+		if err != nil {
+			return Bitboard{}, fmt.Errorf("blocked cell: %w", err)
+		}
+		if position.Cell(cellN) != player.Empty {
+			return Bitboard{}, fmt.Errorf("blocked cell %s is not empty", name)
+		}
+		blocked = blocked.or(bit(cellN))
+	}
+	return blocked, nil
+}
+
+// apply начальная позиция и очередь хода по правилам варианта,
+// закрытые клетки должны пройти Check
+func (variant Variant) apply(position Position, turn player.Color) (Position, player.Color) {
+	blocked, _ := variant.blocked(position)
+	position = position.WithBlocked(position.Blocked().or(blocked))
+
+	rnd := rand.New(rand.NewSource(variant.Seed))
+	for played := 0; played < variant.RandomMoves && !position.IsTerminal(); turn = turn.Opponent() {
+		moves := position.LegalMoves(turn)
+		if len(moves) > 0 { // пропуски не считаются
+			position, _ = position.Apply(moves[rnd.Intn(len(moves))])
+			played++
+		}
+	}
+	return position, turn
+}
+
+// winner победитель по числу фишек, player.Empty при ничьей
+func (variant Variant) winner(green, red int) player.Color {
+	if variant.Anti {
+		green, red = red, green
+	}
+	switch {
+	case green > red:
+		return player.Green
+	case red > green:
+		return player.Red
+	}
+	return player.Empty
+}
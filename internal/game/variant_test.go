@@ -0,0 +1,70 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/slonegd-go/reversi/internal/player"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVariant_anti(t *testing.T) {
+	p1, p2 := &variantPlayer{mockPlayer: mockPlayer{steps: []string{"C1", "H6"}}}, &variantPlayer{}
+	game := New(p1, p2, WithAnti())
+	fill(game, "D4:Empty,E4:Empty,D5:Empty,E5:Empty,A1:Green,B1:Red,H8:Green,H7:Red")
+	result := game.Start(context.Background())
+	assert.Equal(t, player.Red, result.Winner)
+	assert.Equal(t, player.Lose, p1.result)
+	assert.Equal(t, player.Win, p2.result)
+	assert.Equal(t, Variant{Anti: true}, p1.variant)
+	assert.Equal(t, Variant{Anti: true}, game.Variant())
+}
+
+func TestVariant_blocked(t *testing.T) {
+	game := New(&mockPlayer{}, &mockPlayer{}, WithBlocked("E6", "A1"), WithRenderer(PlainRenderer{}))
+	assert.Equal(t, "[D3 C4 F5]", fmt.Sprint(game.Position().LegalMoves(Green)))
+	assert.EqualError(t, game.Step(Green, "E6"), "cell blocked")
+	assert.Equal(t, player.Empty, game.Position().Cell(n("E6")))
+	assert.Equal(t, 58, game.Position().Empties().Count())
+	assert.Equal(t, []int{n("A1"), n("E6")}, game.Position().Blocked().Cells())
+	assert.Contains(t, game.String(), " #")
+
+	assert.EqualError(t, Variant{Blocked: []string{"D4"}}.Check(NewPosition()), "blocked cell D4 is not empty")
+	assert.EqualError(t, Variant{Blocked: []string{"I1"}}.Check(NewPosition()),
+		"blocked cell: position must be from A1 to H8, got: I1")
+	logs := []string{}
+	game = New(&mockPlayer{}, &mockPlayer{}, WithBlocked("D4"),
+		WithLogger(func(format string, args ...interface{}) { logs = append(logs, fmt.Sprintf(format, args...)) }))
+	assert.Equal(t, "variant: blocked cell D4 is not empty, blocked cells ignored", logs[0])
+	assert.Equal(t, Variant{}, game.Variant())
+	assert.Equal(t, NewPosition(), game.Position())
+}
+
+func TestVariant_randomStart(t *testing.T) {
+	a := New(&mockPlayer{}, &mockPlayer{}, WithRandomStart(6, 1))
+	b := New(&mockPlayer{}, &mockPlayer{}, WithRandomStart(6, 1))
+	position, turn := a.Initial()
+	assert.Equal(t, 10, position.Count(Green)+position.Count(Red))
+	assert.Equal(t, player.Green, turn)
+	assert.Equal(t, position, b.Position())
+	assert.Empty(t, a.Moves())
+}
+
+func TestVariant_String(t *testing.T) {
+	tests := map[string]Variant{
+		"standard":                               {},
+		"anti":                                   {Anti: true},
+		"random 4 moves (seed 2), blocked A1 H8": {RandomMoves: 4, Seed: 2, Blocked: []string{"A1", "H8"}},
+	}
+	for want, variant := range tests {
+		assert.Equal(t, want, variant.String())
+	}
+}
+
+type variantPlayer struct {
+	mockPlayer
+	variant Variant
+}
+
+func (p *variantPlayer) SetVariant(v Variant) { p.variant = v }
@@ -0,0 +1,78 @@
+package game
+
+import "github.com/slonegd-go/reversi/internal/player"
+
+// zobrist случайные ключи для хеша позиции: хеш - это xor ключей всех фишек,
+// заблокированных клеток и размера поля. Ход меняет хеш на xor ключей
+// поставленной и перевёрнутых фишек, поэтому считать его заново не нужно.
+var zobrist = func() (keys struct {
+	green, red, blocked [MaxSize * MaxSize]uint64
+	size                [MaxSize + 1]uint64
+	redTurn             uint64
+}) {
+	// splitmix64 с постоянным зерном, чтобы хеши совпадали между запусками
+	state := uint64(0x5eed)
+	next := func() uint64 {
+		state += 0x9e3779b97f4a7c15
+		z := state
+		z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+		z = (z ^ z>>27) * 0x94d049bb133111eb
+		return z ^ z>>31
+	}
+	for i := range keys.green {
+		keys.green[i], keys.red[i], keys.blocked[i] = next(), next(), next()
+	}
+	for i := range keys.size {
+		keys.size[i] = next()
+	}
+	keys.redTurn = next()
+	return keys
+}()
+
+// Hash 64-битный хеш Зобриста расположения фишек, без учёта очереди хода
+func (position Position) Hash() uint64 {
+	return position.hash
+}
+
+// HashTurn хеш позиции вместе с тем, чей ход, для таблиц транспозиций
+func (position Position) HashTurn(turn player.Color) uint64 {
+	if turn == player.Red {
+		return position.hash ^ zobrist.redTurn
+	}
+	return position.hash
+}
+
+// Hash хеш текущей позиции вместе с тем, чей ход
+func (game *Game) Hash() uint64 {
+	return game.position.HashTurn(game.turn)
+}
+
+// rehash позиция с хешем, посчитанным заново
+func (position Position) rehash() Position {
+	position.hash = zobrist.size[position.Size()] ^
+		discsHash(position.green, &zobrist.green) ^
+		discsHash(position.red, &zobrist.red) ^
+		discsHash(position.blocked, &zobrist.blocked)
+	return position
+}
+
+// moveHash изменение хеша от хода в клетку cellN, который перевернул flipped.
+// Повторный xor отменяет ход.
+func moveHash(color player.Color, cellN int, flipped Bitboard) uint64 {
+	result := zobrist.green[cellN]
+	if color == player.Red {
+		result = zobrist.red[cellN]
+	}
+	flipped.each(func(flip int) {
+		result ^= zobrist.green[flip] ^ zobrist.red[flip]
+	})
+	return result
+}
+
+func discsHash(b Bitboard, keys *[MaxSize * MaxSize]uint64) uint64 {
+	var result uint64
+	b.each(func(cellN int) {
+		result ^= keys[cellN]
+	})
+	return result
+}
@@ -0,0 +1,47 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/slonegd-go/reversi/internal/player"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGame_Hash_incremental(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for n := 0; n < 50; n++ {
+		game := New(&mockPlayer{}, &mockPlayer{})
+		start := game.Hash()
+		moves := randomGame(rnd)
+		for _, move := range moves {
+			if !assert.NoError(t, game.play(move)) {
+				return
+			}
+			assert.Equal(t, game.position.rehash().Hash(), game.position.Hash())
+		}
+		for game.Undo() == nil {
+			assert.Equal(t, game.position.rehash().Hash(), game.position.Hash())
+		}
+		assert.Equal(t, start, game.Hash())
+	}
+}
+
+func TestPosition_Hash(t *testing.T) {
+	start := NewPosition()
+	assert.NotEqual(t, start.HashTurn(Green), start.HashTurn(Red))
+
+	a, _ := start.Apply(Move{Color: Green, Cell: n("D3")})
+	b, _ := start.Apply(Move{Color: Green, Cell: n("C4")})
+	assert.NotEqual(t, a.Hash(), b.Hash())
+
+	same, _ := PositionFromCells(a.Cells())
+	assert.Equal(t, a.Hash(), same.Hash())
+
+	small, _ := NewPositionSize(6)
+	empty4, _ := PositionFromCells(make([]player.Color, 16))
+	empty6, _ := PositionFromCells(make([]player.Color, 36))
+	assert.NotEqual(t, empty4.Hash(), empty6.Hash())
+	assert.NotEqual(t, small.Hash(), start.Hash())
+	assert.NotEqual(t, start.Hash(), start.WithBlocked(bit(0)).Hash())
+}
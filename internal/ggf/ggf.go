@@ -0,0 +1,94 @@
+// Package ggf читает и пишет партии в формате GGF (Generic Game Format),
+// которым обмениваются программы для игры в отелло.
+//
+//	(;GM[Othello]PB[black]PW[white]RE[+4.000]BO[8 ... *]B[f5//0.01]W[d6]...;)
+//
+// Чёрные в GGF играют зелёными фишками, белые - красными.
+package ggf
+
+import (
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+type Player struct {
+	Name   string
+	Rating float64
+}
+
+// forcedPass вынужденный пропуск (game.Move.Forced). В GGF такого нет,
+// поэтому он отличается от обычного "PA", чтобы партию можно было воспроизвести.
+const forcedPass = "PA!"
+
+// Move ход с необязательной оценкой позиции и затраченным временем в секундах
+type Move struct {
+	game.Move
+	Eval float64
+	Time float64
+}
+
+// Result итог партии
+type Result struct {
+	Known  bool    // в записи может стоять "?"
+	Score  float64 // разница фишек в пользу чёрных
+	Reason game.Reason
+}
+
+// Record одна партия
+type Record struct {
+	Place      string // PC
+	Date       string // DT
+	Black      Player // PB, RB
+	White      Player // PW, RW
+	Time       string // TI, контроль времени
+	Result     Result // RE
+	Start      game.Position
+	ToMove     player.Color
+	Moves      []Move
+	Properties map[string]string // остальные свойства как есть
+}
+
+// NewResult итог партии в терминах GGF
+func NewResult(result game.Result) Result {
+	return Result{
+		Known:  true,
+		Score:  float64(result.Green - result.Red),
+		Reason: result.Reason,
+	}
+}
+
+// FromGame запись сыгранных в игре ходов.
+// Итог заполняется, если позиция окончена, иначе его можно задать через NewResult.
+func FromGame(g *game.Game) Record {
+	start, toMove := g.Initial()
+	record := Record{
+		Start:  start,
+		ToMove: toMove,
+	}
+	for _, move := range g.Moves() {
+		record.Moves = append(record.Moves, Move{Move: move})
+	}
+	if position := g.Position(); position.IsTerminal() {
+		record.Result = Result{Known: true, Score: float64(position.Score())}
+	}
+	return record
+}
+
+// GameMoves ходы записи без оценок и времени
+func (record Record) GameMoves() []game.Move {
+	result := make([]game.Move, 0, len(record.Moves))
+	for _, move := range record.Moves {
+		result = append(result, move.Move)
+	}
+	return result
+}
+
+// NewGame игра с позиции записи, в которой сыграны все её ходы
+func (record Record) NewGame(p1, p2 player.Player, opts ...game.Option) (*game.Game, error) {
+	opts = append(opts, game.WithPosition(record.Start, record.ToMove))
+	result := game.New(p1, p2, opts...)
+	if err := result.Replay(record.GameMoves(), -1); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
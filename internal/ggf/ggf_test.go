@@ -0,0 +1,152 @@
+package ggf
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player"
+	"github.com/slonegd-go/reversi/internal/player/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+const games = `
+(;GM[Othello]PC[GGS/os]DT[2003.12.15_13:24:03.MET]PB[Saio1200]PW[Zebra]RB[2197.45]RW[1874.12]TI[05:00//02:00]TY[8]RE[+4.000]BO[8 -------- -------- -------- ---O*--- ---*O--- -------- -------- -------- *]B[f5//0.01]W[d6/-1.5/0.2]B[c3]W[d3]B[c4];)
+(;GM[Othello]PB[a]PW[b]RE[-64.000:r]BO[8 -------- -------- -------- ---O*--- ---*O--- -------- -------- -------- *]B[f5];)
+`
+
+func TestParse(t *testing.T) {
+	records, err := Parse(games)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, records, 2)
+
+	record := records[0]
+	assert.Equal(t, "GGS/os", record.Place)
+	assert.Equal(t, Player{Name: "Saio1200", Rating: 2197.45}, record.Black)
+	assert.Equal(t, Player{Name: "Zebra", Rating: 1874.12}, record.White)
+	assert.Equal(t, "05:00//02:00", record.Time)
+	assert.Equal(t, map[string]string{"TY": "8"}, record.Properties)
+	assert.Equal(t, Result{Known: true, Score: 4}, record.Result)
+	assert.Equal(t, player.Green, record.ToMove)
+	assert.Equal(t, player.Green, record.Start.Cell(cell("E4")))
+	assert.Equal(t, player.Red, record.Start.Cell(cell("D4")))
+	assert.Equal(t, "[F5 D6 C3 D3 C4]", fmt.Sprint(record.GameMoves()))
+	assert.Equal(t, Move{Move: game.Move{Color: player.Red, Cell: cell("D6")}, Eval: -1.5, Time: 0.2}, record.Moves[1])
+	assert.Equal(t, Result{Known: true, Score: -64, Reason: game.Resignation}, records[1].Result)
+}
+
+func TestParse_errors(t *testing.T) {
+	board := "BO[8 -------- -------- -------- ---O*--- ---*O--- -------- -------- -------- *]"
+	tests := map[string]struct {
+		s       string
+		wantErr string
+	}{
+		"no start":      {s: "GM[Othello];)", wantErr: `game 1: expected "(;" at offset 0`},
+		"unterminated":  {s: "(;GM[Othello]PB[a", wantErr: "game 1: property PB at offset 13: missing \"]\""},
+		"no end":        {s: "(;GM[Othello]", wantErr: "game 1: unterminated game at offset 13"},
+		"bad key":       {s: "(;GM[Othello]pb[a];)", wantErr: `game 1: unexpected 'p' at offset 13`},
+		"other game":    {s: "(;GM[Go];)", wantErr: "game 1: property GM[Go]: only Othello games supported"},
+		"no board":      {s: "(;GM[Othello];)", wantErr: "game 1: missing BO property"},
+		"bad rating":    {s: "(;RB[strong];)", wantErr: `game 1: property RB[strong]: strconv.ParseFloat: parsing "strong": invalid syntax`},
+		"bad board":     {s: "(;BO[8 x *];)", wantErr: `game 1: property BO[8 x *]: unknown cell 'x'`},
+		"board size":    {s: "(;BO[12 - *];)", wantErr: "game 1: property BO[12 - *]: unsupported board size 12"},
+		"board cells":   {s: "(;BO[6 ------ *];)", wantErr: "game 1: property BO[6 ------ *]: board must have 36 cells, got: 6"},
+		"bad move":      {s: "(;" + board + "B[z9];)", wantErr: "game 1: property B[z9]: position must be from A1 to H8, got: Z9"},
+		"illegal move":  {s: "(;" + board + "B[a1];)", wantErr: "game 1: move 1 A1: unavailable step"},
+		"wrong turn":    {s: "(;" + board + "W[d3];)", wantErr: "game 1: move 1 D3: green turn"},
+		"wrong pass":    {s: "(;" + board + "B[PA];)", wantErr: "game 1: move 1 pass: pass with available steps"},
+		"second broken": {s: "(;" + board + ";)(;" + board + "B[a1];)", wantErr: "game 2: move 1 A1: unavailable step"},
+	}
+	for name, tt := range tests {
+		_, err := Parse(tt.s)
+		assert.EqualError(t, err, tt.wantErr, name)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	records, err := Parse(games)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var buffer bytes.Buffer
+	assert.NoError(t, Write(&buffer, records...))
+	assert.Equal(t, "(;GM[Othello]PC[GGS/os]DT[2003.12.15_13:24:03.MET]PB[Saio1200]PW[Zebra]RB[2197.45]RW[1874.12]TI[05:00//02:00]TY[8]RE[+4.000]"+
+		"BO[8 -------- -------- -------- ---O*--- ---*O--- -------- -------- -------- *]B[f5//0.01]W[d6/-1.5/0.2]B[c3]W[d3]B[c4];)\n"+
+		"(;GM[Othello]PB[a]PW[b]RE[-64.000:r]BO[8 -------- -------- -------- ---O*--- ---*O--- -------- -------- -------- *]B[f5];)\n",
+		buffer.String())
+
+	again, err := Read(&buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, records, again)
+}
+
+func TestWrite_size(t *testing.T) {
+	s := "(;GM[Othello]RE[?]BO[10 ---------- ---------- ---------- ---------- ----O*---- ----*O---- ---------- ---------- ---------- ---------- *]B[e4]W[d4];)\n"
+	records, err := Parse(s)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 10, records[0].Start.Size())
+	var buffer bytes.Buffer
+	assert.NoError(t, Write(&buffer, records...))
+	assert.Equal(t, s, buffer.String())
+}
+
+func TestRecord_NewGame(t *testing.T) {
+	records, err := Parse(games)
+	if !assert.NoError(t, err) {
+		return
+	}
+	g, err := records[0].NewGame(&cli.Player{}, &cli.Player{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, records[0].GameMoves(), g.Moves())
+
+	assert.Equal(t, game.NewPosition(), records[0].Start)
+	assert.Equal(t, "f5d6c3d3c4", g.Transcript(), "в записи те же клетки, что в GGF")
+	moves, err := game.ParseTranscript(g.Transcript(), 8)
+	assert.NoError(t, err)
+	assert.Equal(t, records[0].GameMoves(), moves)
+
+	record := FromGame(g)
+	assert.Equal(t, records[0].Start, record.Start)
+	assert.Equal(t, records[0].GameMoves(), record.GameMoves())
+	assert.False(t, record.Result.Known)
+}
+
+func TestRecord_forcedPass(t *testing.T) {
+	g := game.New(&cli.Player{}, &cli.Player{})
+	moves := []game.Move{{Color: player.Green, Cell: game.Pass, Forced: true}, {Color: player.Red, Cell: cell("D6")}}
+	if !assert.NoError(t, g.Replay(moves, -1)) {
+		return
+	}
+	var buffer bytes.Buffer
+	assert.NoError(t, Write(&buffer, FromGame(g)))
+	assert.Contains(t, buffer.String(), "B[PA!]W[d6]")
+
+	records, err := Read(&buffer)
+	if !assert.NoError(t, err) {
+		return
+	}
+	again, err := records[0].NewGame(&cli.Player{}, &cli.Player{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, moves, again.Moves())
+	assert.Equal(t, g.Position(), again.Position())
+}
+
+//
+//
+// helpers and mocks
+//
+//
+
+func cell(name string) int {
+	result, _ := game.ParseCell(name, 8)
+	return result
+}
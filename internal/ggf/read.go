@@ -0,0 +1,288 @@
+package ggf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// Read читает все партии
+func Read(r io.Reader) ([]Record, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(data))
+}
+
+// Parse разбирает все партии из строки, проверяя ходы каждой
+func Parse(s string) ([]Record, error) {
+	parser := &parser{s: s}
+	result := []Record{}
+	for {
+		parser.skipSpaces()
+		if parser.i == len(s) {
+			return result, nil
+		}
+		record, err := parser.record()
+		if err == nil {
+			err = validate(record)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("game %d: %w", len(result)+1, err)
+		}
+		result = append(result, record)
+	}
+}
+
+type parser struct {
+	s string
+	i int
+}
+
+func (parser *parser) skipSpaces() {
+	for parser.i < len(parser.s) && strings.ContainsRune(" \t\r\n", rune(parser.s[parser.i])) {
+		parser.i++
+	}
+}
+
+func (parser *parser) consume(prefix string) bool {
+	if strings.HasPrefix(parser.s[parser.i:], prefix) {
+		parser.i += len(prefix)
+		return true
+	}
+	return false
+}
+
+func (parser *parser) record() (Record, error) {
+	record := Record{Properties: map[string]string{}}
+	if !parser.consume("(;") {
+		return record, fmt.Errorf("expected \"(;\" at offset %d", parser.i)
+	}
+	hasBoard := false
+	moves := [][2]string{} // ходы разбираются, когда известен размер поля
+	for {
+		parser.skipSpaces()
+		if parser.consume(";)") {
+			break
+		}
+		key, value, err := parser.property()
+		if err != nil {
+			return record, err
+		}
+		switch key {
+		case "B", "W":
+			moves = append(moves, [2]string{key, value})
+			continue
+		case "BO":
+			hasBoard = true
+		}
+		if err := record.set(key, value); err != nil {
+			return record, fmt.Errorf("property %s[%s]: %w", key, value, err)
+		}
+	}
+	if !hasBoard {
+		return record, errors.New("missing BO property")
+	}
+	for _, move := range moves {
+		color := player.Green
+		if move[0] == "W" {
+			color = player.Red
+		}
+		parsed, err := parseMove(color, move[1], record.Start.Size())
+		if err != nil {
+			return record, fmt.Errorf("property %s[%s]: %w", move[0], move[1], err)
+		}
+		record.Moves = append(record.Moves, parsed)
+	}
+	return record, nil
+}
+
+func (parser *parser) property() (key, value string, err error) {
+	start := parser.i
+	for parser.i < len(parser.s) && parser.s[parser.i] >= 'A' && parser.s[parser.i] <= 'Z' {
+		parser.i++
+	}
+	key = parser.s[start:parser.i]
+	if parser.i == len(parser.s) {
+		return "", "", fmt.Errorf("unterminated game at offset %d", start)
+	}
+	if key == "" || !parser.consume("[") {
+		return "", "", fmt.Errorf("unexpected %q at offset %d", parser.s[parser.i], parser.i)
+	}
+	end := strings.IndexByte(parser.s[parser.i:], ']')
+	if end < 0 {
+		return "", "", fmt.Errorf("property %s at offset %d: missing \"]\"", key, start)
+	}
+	value = parser.s[parser.i : parser.i+end]
+	parser.i += end + 1
+	return key, value, nil
+}
+
+func (record *Record) set(key, value string) (err error) {
+	switch key {
+	case "GM":
+		if !strings.EqualFold(value, "othello") {
+			return errors.New("only Othello games supported")
+		}
+	case "PC":
+		record.Place = value
+	case "DT":
+		record.Date = value
+	case "PB":
+		record.Black.Name = value
+	case "PW":
+		record.White.Name = value
+	case "RB":
+		record.Black.Rating, err = strconv.ParseFloat(value, 64)
+	case "RW":
+		record.White.Rating, err = strconv.ParseFloat(value, 64)
+	case "TI":
+		record.Time = value
+	case "RE":
+		record.Result, err = parseResult(value)
+	case "BO":
+		record.Start, record.ToMove, err = parseBoard(value)
+	default:
+		record.Properties[key] = value
+	}
+	return err
+}
+
+func parseResult(value string) (Result, error) {
+	var result Result
+	if value == "?" {
+		return result, nil
+	}
+	score := value
+	if i := strings.IndexByte(value, ':'); i >= 0 {
+		score = value[:i]
+		switch value[i+1:] {
+		case "r":
+			result.Reason = game.Resignation
+		case "t":
+			result.Reason = game.Timeout
+		case "s":
+		default:
+			return result, fmt.Errorf("unknown result kind %q", value[i+1:])
+		}
+	}
+	var err error
+	result.Score, err = strconv.ParseFloat(score, 64)
+	if err != nil {
+		return result, err
+	}
+	result.Known = true
+	return result, nil
+}
+
+func parseBoard(value string) (game.Position, player.Color, error) {
+	var position game.Position
+	fields := strings.Fields(value)
+	if len(fields) < 3 {
+		return position, player.Empty, errors.New("board must have size, cells and side to move")
+	}
+	size, err := strconv.Atoi(fields[0])
+	if err != nil || game.CheckSize(size) != nil {
+		return position, player.Empty, fmt.Errorf("unsupported board size %s", fields[0])
+	}
+	board := strings.Join(fields[1:len(fields)-1], "")
+	cells := make([]player.Color, 0, len(board))
+	for _, char := range board {
+		color, err := parseColor(char)
+		if err != nil {
+			return position, player.Empty, err
+		}
+		cells = append(cells, color)
+	}
+	if len(board) != size*size {
+		return position, player.Empty, fmt.Errorf("board must have %d cells, got: %d", size*size, len(board))
+	}
+	position, err = game.PositionFromCells(cells)
+	if err != nil {
+		return position, player.Empty, err
+	}
+	toMove := fields[len(fields)-1]
+	if len(toMove) != 1 {
+		return position, player.Empty, fmt.Errorf("bad side to move %q", toMove)
+	}
+	color, err := parseColor(rune(toMove[0]))
+	if err == nil && color == player.Empty {
+		err = fmt.Errorf("bad side to move %q", toMove)
+	}
+	return position, color, err
+}
+
+func parseColor(char rune) (player.Color, error) {
+	switch char {
+	case '-':
+		return player.Empty, nil
+	case '*':
+		return player.Green, nil
+	case 'O':
+		return player.Red, nil
+	}
+	return player.Empty, fmt.Errorf("unknown cell %q", char)
+}
+
+func parseMove(color player.Color, value string, size int) (Move, error) {
+	move := Move{Move: game.Move{Color: color}}
+	parts := strings.Split(value, "/")
+	if len(parts) > 3 {
+		return move, errors.New("move must be cell/eval/time")
+	}
+	switch {
+	case strings.EqualFold(parts[0], forcedPass):
+		move.Cell, move.Forced = game.Pass, true
+	case strings.EqualFold(parts[0], "pa") || strings.EqualFold(parts[0], "pass"):
+		move.Cell = game.Pass
+	default:
+		cellN, err := game.ParseCell(parts[0], size)
+		if err != nil {
+			return move, err
+		}
+		move.Cell = cellN
+	}
+	var err error
+	if len(parts) > 1 && parts[1] != "" {
+		if move.Eval, err = strconv.ParseFloat(parts[1], 64); err != nil {
+			return move, fmt.Errorf("eval: %w", err)
+		}
+	}
+	if len(parts) > 2 && parts[2] != "" {
+		if move.Time, err = strconv.ParseFloat(parts[2], 64); err != nil {
+			return move, fmt.Errorf("time: %w", err)
+		}
+	}
+	return move, nil
+}
+
+// validate проверяет очерёдность и допустимость ходов
+func validate(record Record) error {
+	position, turn := record.Start, record.ToMove
+	for i, move := range record.Moves {
+		var err error
+		switch {
+		case move.Color != turn:
+			err = fmt.Errorf("%s turn", turn.Name())
+		case position.IsTerminal():
+			err = errors.New("game is over")
+		case move.IsPass() && !move.Forced && position.HasMoves(turn):
+			err = errors.New("pass with available steps")
+		case !move.IsPass() && position.Flips(move.Move).IsZero():
+			err = errors.New("unavailable step")
+		}
+		if err != nil {
+			return fmt.Errorf("move %d %s: %w", i+1, move.Name(position.Size()), err)
+		}
+		position, _ = position.Apply(move.Move)
+		turn = turn.Opponent()
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package ggf
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// Write пишет партии, по одной на строку
+func Write(w io.Writer, records ...Record) error {
+	for _, record := range records {
+		if _, err := fmt.Fprintln(w, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (record Record) String() string {
+	var builder strings.Builder
+	builder.WriteString("(;GM[Othello]")
+	property := func(key, value string) {
+		if value == "" {
+			return
+		}
+		builder.WriteString(key)
+		builder.WriteByte('[')
+		builder.WriteString(value)
+		builder.WriteByte(']')
+	}
+	property("PC", record.Place)
+	property("DT", record.Date)
+	property("PB", record.Black.Name)
+	property("PW", record.White.Name)
+	property("RB", formatFloat(record.Black.Rating))
+	property("RW", formatFloat(record.White.Rating))
+	property("TI", record.Time)
+
+	keys := make([]string, 0, len(record.Properties))
+	for key := range record.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		property(key, record.Properties[key])
+	}
+
+	property("RE", record.Result.String())
+	property("BO", formatBoard(record.Start, record.ToMove))
+	for _, move := range record.Moves {
+		key := "B"
+		if move.Color == player.Red {
+			key = "W"
+		}
+		property(key, move.format(record.Start.Size()))
+	}
+	builder.WriteString(";)")
+	return builder.String()
+}
+
+func (result Result) String() string {
+	if !result.Known {
+		return "?"
+	}
+	s := fmt.Sprintf("%+.3f", result.Score)
+	switch result.Reason {
+	case game.Resignation:
+		s += ":r"
+	case game.Timeout:
+		s += ":t"
+	}
+	return s
+}
+
+func (move Move) format(size int) string {
+	s := "PA"
+	switch {
+	case move.Forced:
+		s = forcedPass
+	case !move.IsPass():
+		s = strings.ToLower(game.CellName(move.Cell, size))
+	}
+	if move.Eval != 0 || move.Time != 0 {
+		s += "/" + formatFloat(move.Eval) + "/" + formatFloat(move.Time)
+	}
+	return s
+}
+
+func formatBoard(position game.Position, toMove player.Color) string {
+	var builder strings.Builder
+	size := position.Size()
+	builder.WriteString(strconv.Itoa(size))
+	for i, color := range position.Cells() {
+		if i%size == 0 {
+			builder.WriteByte(' ')
+		}
+		builder.WriteByte(colorChar(color))
+	}
+	builder.WriteByte(' ')
+	builder.WriteByte(colorChar(toMove))
+	return builder.String()
+}
+
+func colorChar(color player.Color) byte {
+	switch color {
+	case player.Green:
+		return '*'
+	case player.Red:
+		return 'O'
+	}
+	return '-'
+}
+
+func formatFloat(f float64) string {
+	if f == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -2,26 +2,85 @@ package cli
 
 import (
 	"bufio"
+	"context"
+	"errors"
+	"io"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/slonegd-go/reversi/internal/player"
 )
 
 type Player struct {
 	color player.Color
+	in    io.Reader // откуда читать ходы, по умолчанию os.Stdin
+
+	once        sync.Once
+	lines       chan string   // строки из in, их читает одна горутина на всю игру
+	interrupted chan struct{} // игра бросила ход
 }
 
-func (player *Player) Step(colors []player.Color, _ []bool, step func(string) error) {
-	reader := bufio.NewReader(os.Stdin)
+// Step читает ходы по строке, пока игра не примет один.
+// Если ход отменён или прерван через Interrupt, Step возвращается без хода.
+// Когда читать больше нечего, Step ждёт, пока его прервут.
+func (p *Player) Step(colors []player.Color, _ []bool, step func(string) error) {
+	p.init()
 	for {
-		result, _ := reader.ReadString('\n')
-		err := step(strings.TrimSpace(result))
-		if err == nil {
+		select {
+		case <-p.interrupted:
 			return
+		case result, ok := <-p.lines:
+			if !ok {
+				<-p.interrupted
+				return
+			}
+			result = strings.TrimSpace(result)
+			if strings.EqualFold(result, player.Undo) {
+				result = player.Undo
+			}
+			err := step(result)
+			if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return
+			}
 		}
 	}
 }
-func (player *Player) Notify(player.Result)    {}
-func (player *Player) SetColor(v player.Color) { player.color = v }
-func (player *Player) Color() player.Color     { return player.color }
+
+// Interrupt прерывает ход, который игра бросила, чтобы Step не читал дальше.
+// Если Step уже вернулся, без хода вернётся следующий, и игра спросит снова.
+func (p *Player) Interrupt() {
+	p.init()
+	select {
+	case p.interrupted <- struct{}{}:
+	default:
+	}
+}
+
+func (p *Player) init() {
+	p.once.Do(func() {
+		in := p.in
+		if in == nil {
+			in = os.Stdin
+		}
+		p.lines = make(chan string)
+		p.interrupted = make(chan struct{}, 1)
+		go func() {
+			defer close(p.lines)
+			reader := bufio.NewReader(in)
+			for {
+				line, err := reader.ReadString('\n')
+				if line != "" {
+					p.lines <- line
+				}
+				if err != nil {
+					return
+				}
+			}
+		}()
+	})
+}
+
+func (p *Player) Notify(player.Result)    {}
+func (p *Player) SetColor(v player.Color) { p.color = v }
+func (p *Player) Color() player.Color     { return p.color }
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slonegd-go/reversi/internal/player"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlayer_Step(t *testing.T) {
+	p := &Player{in: strings.NewReader("z9\n UNDO \nE6")}
+	got := []string{}
+	p.Step(nil, nil, func(s string) error {
+		got = append(got, s)
+		if s != "E6" {
+			return errors.New("unavailable step")
+		}
+		return nil
+	})
+	assert.Equal(t, []string{"z9", player.Undo, "E6"}, got)
+
+	p = &Player{in: strings.NewReader("z9\nE6\n")}
+	got = []string{}
+	p.Step(nil, nil, func(s string) error {
+		got = append(got, s)
+		return context.Canceled
+	})
+	assert.Equal(t, []string{"z9"}, got, "stops when the move is cancelled")
+}
+
+func TestPlayer_Interrupt(t *testing.T) {
+	tests := map[string]io.Reader{
+		"waiting": func() io.Reader { r, _ := io.Pipe(); return r }(),
+		"closed":  strings.NewReader(""),
+	}
+	for name, in := range tests {
+		p := &Player{in: in}
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			p.Step(nil, nil, func(string) error { return errors.New("unavailable step") })
+		}()
+		p.Interrupt()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Errorf("%s: Step is not interrupted", name)
+		}
+	}
+}
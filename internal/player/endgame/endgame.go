@@ -0,0 +1,268 @@
+// Package endgame точное решение конца партии перебором до последнего хода.
+// Когда пустых клеток мало, решение находит лучший ход и итоговую разницу фишек
+// при лучшей игре обеих сторон.
+package endgame
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// MaxEmpties больше пустых клеток решение не ищет, перебор занял бы слишком много времени
+const MaxEmpties = 20
+
+// Mode что нужно узнать о позиции
+type Mode int
+
+const (
+	Exact Mode = iota // точная разница фишек
+	WLD               // только выигрыш, ничья или проигрыш, это быстрее
+)
+
+func (mode Mode) String() string {
+	switch mode {
+	case Exact:
+		return "exact"
+	case WLD:
+		return "wld"
+	default:
+		return fmt.Sprintf("undefined(%d)", int(mode))
+	}
+}
+
+// Solver решает позиции в режиме Mode. В антиреверси (Anti) выигрывает тот,
+// у кого меньше фишек, и разница считается в его пользу.
+type Solver struct {
+	Mode Mode
+	Anti bool
+}
+
+// Solution решение позиции
+type Solution struct {
+	Move  game.Move
+	Score int // итоговая разница фишек в пользу ходящего, в WLD только знак: 1, 0 или -1
+	Nodes int // просмотрено позиций
+}
+
+// fastestFirst начиная с этого числа пустых клеток ходы упорядочиваются по подвижности
+// соперника, ближе к концу это дороже, чем сам перебор
+const fastestFirst = 7
+
+// Solve лучший ход color и итог партии при лучшей игре. Если ходить нечем,
+// в решении пропуск. Решение прерывается с ошибкой, когда ctx отменён.
+func (solver Solver) Solve(ctx context.Context, position game.Position, color player.Color) (Solution, error) {
+	if empties := position.Empties().Count(); empties > MaxEmpties {
+		return Solution{}, fmt.Errorf("too many empties %d, want at most %d", empties, MaxEmpties)
+	}
+	s := &solve{ctx: ctx, anti: solver.Anti, table: map[uint64]bounds{}}
+	alpha, beta := -bound, bound
+	if solver.Mode == WLD {
+		alpha, beta = -1, 1
+	}
+	solution := Solution{Move: game.Move{Color: color, Cell: game.Pass}}
+	legal := position.Legal(color)
+	if legal.IsZero() {
+		solution.Score = s.negamax(position, color, alpha, beta, false)
+	} else {
+		solution.Score = alpha
+		for i, cellN := range s.order(position, color, legal) {
+			next, _ := position.Apply(game.Move{Color: color, Cell: cellN})
+			score := -s.negamax(next, color.Opponent(), -beta, -solution.Score, false)
+			if i == 0 || score > solution.Score {
+				solution.Move.Cell, solution.Score = cellN, score
+			}
+			if solution.Score >= beta {
+				break
+			}
+		}
+	}
+	solution.Nodes = s.nodes
+	if s.aborted {
+		return Solution{}, ctx.Err()
+	}
+	if solver.Mode == WLD {
+		solution.Score = sign(solution.Score)
+	}
+	return solution, nil
+}
+
+// Label точные итоги для примеров, в позициях которых не больше MaxEmpties пустых клеток:
+// Score становится итогом партии при лучшей игре после сделанного хода.
+// Остальные примеры не меняются.
+func (solver Solver) Label(ctx context.Context, samples []game.Sample) ([]game.Sample, error) {
+	result := make([]game.Sample, len(samples))
+	for i, sample := range samples {
+		result[i] = sample
+		if sample.Position.Empties().Count() > MaxEmpties {
+			continue
+		}
+		next, flipped := sample.Position.Apply(sample.Move)
+		if flipped.IsZero() {
+			return nil, fmt.Errorf("sample %d: unavailable step %s", i+1, sample.Move.Name(sample.Position.Size()))
+		}
+		solution, err := solver.Solve(ctx, next, sample.Move.Color.Opponent())
+		if err != nil {
+			return nil, fmt.Errorf("sample %d: %w", i+1, err)
+		}
+		result[i].Score = -solution.Score
+	}
+	return result, nil
+}
+
+// bound больше любой разницы фишек
+const bound = game.MaxSize*game.MaxSize + 1
+
+// solve состояние одного решения
+type solve struct {
+	ctx     context.Context
+	anti    bool
+	nodes   int
+	aborted bool
+	table   map[uint64]bounds // уже решённые позиции с ходом
+}
+
+// bounds границы итога позиции, найденные прошлыми окнами
+type bounds struct {
+	lower, upper int
+}
+
+// tableEmpties с этого числа пустых клеток позиции запоминаются,
+// ближе к концу перебор дешевле
+const tableEmpties = 8
+
+// negamax итог для color с окном (alpha, beta), passed - соперник только что пропустил ход
+func (s *solve) negamax(position game.Position, color player.Color, alpha, beta int, passed bool) int {
+	s.nodes++
+	if s.nodes%4096 == 0 && s.ctx.Err() != nil {
+		s.aborted = true
+	}
+	if s.aborted {
+		return 0
+	}
+	legal := position.Legal(color)
+	if legal.IsZero() {
+		if passed {
+			return s.final(position, color)
+		}
+		return -s.negamax(position, color.Opponent(), -beta, -alpha, true)
+	}
+
+	var hash uint64
+	stored := bounds{lower: -bound, upper: bound}
+	remember := position.Empties().Count() >= tableEmpties
+	if remember {
+		hash = position.HashTurn(color)
+		if b, ok := s.table[hash]; ok {
+			stored = b
+			switch {
+			case b.lower >= beta:
+				return b.lower
+			case b.upper <= alpha:
+				return b.upper
+			case b.lower == b.upper:
+				return b.lower
+			}
+			if b.lower > alpha {
+				alpha = b.lower
+			}
+			if b.upper < beta {
+				beta = b.upper
+			}
+		}
+	}
+
+	window := alpha
+	best := -bound
+	for _, cellN := range s.order(position, color, legal) {
+		next, _ := position.Apply(game.Move{Color: color, Cell: cellN})
+		score := -s.negamax(next, color.Opponent(), -beta, -alpha, false)
+		if score > best {
+			best = score
+		}
+		if score > alpha {
+			alpha = score
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+
+	if remember && !s.aborted {
+		switch {
+		case best <= window:
+			stored.upper = best // все ходы хуже окна, известна только верхняя граница
+		case best >= beta:
+			stored.lower = best
+		default:
+			stored = bounds{lower: best, upper: best}
+		}
+		s.table[hash] = stored
+	}
+	return best
+}
+
+// final разница фишек в пользу color в законченной партии
+func (s *solve) final(position game.Position, color player.Color) int {
+	diff := position.Count(color) - position.Count(color.Opponent())
+	if s.anti {
+		return -diff
+	}
+	return diff
+}
+
+// order ходы по порядку проверки. Сначала ходы, после которых у соперника
+// меньше ответов (fastest-first), при равенстве - в четверти поля с нечётным
+// числом пустых клеток (чётность): в них обычно последний ход остаётся за нами.
+func (s *solve) order(position game.Position, color player.Color, legal game.Bitboard) []int {
+	cells := legal.Cells()
+	if len(cells) < 2 {
+		return cells
+	}
+	size := position.Size()
+	quadrant := func(cellN int) int {
+		row, col := cellN/size, cellN%size
+		return row/(size/2)*2 + col/(size/2)
+	}
+	var empties [4]int
+	for _, cellN := range position.Empties().Cells() {
+		empties[quadrant(cellN)]++
+	}
+	type candidate struct {
+		cellN    int
+		mobility int
+		odd      bool
+	}
+	candidates := make([]candidate, len(cells))
+	fastest := position.Empties().Count() >= fastestFirst
+	for i, cellN := range cells {
+		candidates[i] = candidate{cellN: cellN, odd: empties[quadrant(cellN)]%2 == 1}
+		if fastest {
+			next, _ := position.Apply(game.Move{Color: color, Cell: cellN})
+			candidates[i].mobility = next.Legal(color.Opponent()).Count()
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].mobility != candidates[j].mobility {
+			return candidates[i].mobility < candidates[j].mobility
+		}
+		return candidates[i].odd && !candidates[j].odd
+	})
+	for i, c := range candidates {
+		cells[i] = c.cellN
+	}
+	return cells
+}
+
+func sign(x int) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,153 @@
+package endgame
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player"
+	"github.com/slonegd-go/reversi/internal/player/greedy"
+	"github.com/stretchr/testify/assert"
+)
+
+// minimax перебор без отсечений для сравнения
+func minimax(position game.Position, color player.Color) int {
+	legal := position.LegalMoves(color)
+	if len(legal) == 0 {
+		if !position.HasMoves(color.Opponent()) {
+			return position.Count(color) - position.Count(color.Opponent())
+		}
+		return -minimax(position, color.Opponent())
+	}
+	best := -bound
+	for _, move := range legal {
+		next, _ := position.Apply(move)
+		if score := -minimax(next, color.Opponent()); score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+// randomPosition позиция с empties пустыми клетками после случайных ходов и чей в ней ход
+func randomPosition(rnd *rand.Rand, size, empties int) (game.Position, player.Color) {
+	position, _ := game.NewPositionSize(size)
+	color := player.Green
+	for position.Empties().Count() > empties && !position.IsTerminal() {
+		if legal := position.LegalMoves(color); len(legal) > 0 {
+			position, _ = position.Apply(legal[rnd.Intn(len(legal))])
+		}
+		color = color.Opponent()
+	}
+	return position, color
+}
+
+func TestSolver_Solve(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 30; i++ {
+		size := []int{4, 6, 8}[i%3]
+		position, color := randomPosition(rnd, size, 2+rnd.Intn(8))
+		setup := game.FormatSetup(position, color)
+		want := minimax(position, color)
+
+		exact, err := Solver{}.Solve(context.Background(), position, color)
+		assert.NoError(t, err)
+		assert.Equal(t, want, exact.Score, setup)
+		if !exact.Move.IsPass() {
+			next, _ := position.Apply(exact.Move)
+			assert.Equal(t, want, -minimax(next, color.Opponent()), "лучший ход: %s", setup)
+		}
+
+		wld, err := Solver{Mode: WLD}.Solve(context.Background(), position, color)
+		assert.NoError(t, err)
+		assert.Equal(t, sign(want), wld.Score, setup)
+		assert.True(t, wld.Nodes <= exact.Nodes, setup)
+	}
+}
+
+func TestSolver_Solve_anti(t *testing.T) {
+	// A1 единственный ход и забирает все фишки красного
+	position, color, err := game.ParseSetup("-RRRRRRG GGGGGGGG GGGGGGGG GGGGGGGG GGGGGGGG GGGGGGGG GGGGGGGG GGGGGGG- G")
+	assert.NoError(t, err)
+	solution, err := Solver{}.Solve(context.Background(), position, color)
+	assert.NoError(t, err)
+	assert.Equal(t, game.Move{Color: player.Green, Cell: 0}, solution.Move)
+	assert.Equal(t, 63, solution.Score)
+
+	solution, err = Solver{Anti: true}.Solve(context.Background(), position, color)
+	assert.NoError(t, err)
+	assert.Equal(t, -63, solution.Score, "в антиреверси это проигрыш")
+}
+
+func TestSolver_Solve_errors(t *testing.T) {
+	_, err := Solver{}.Solve(context.Background(), game.NewPosition(), player.Green)
+	assert.EqualError(t, err, "too many empties 60, want at most 20")
+
+	position, color := randomPosition(rand.New(rand.NewSource(1)), 8, 16)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = Solver{}.Solve(ctx, position, color)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestSolver_Label(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	start, _ := game.NewPositionSize(6)
+	position, color := start, player.Green
+	moves := []game.Move{}
+	for !position.IsTerminal() {
+		move := game.Move{Color: color, Cell: game.Pass}
+		if legal := position.LegalMoves(color); len(legal) > 0 {
+			move = legal[rnd.Intn(len(legal))]
+		}
+		position, _ = position.Apply(move)
+		moves = append(moves, move)
+		color = color.Opponent()
+	}
+	all := game.Samples(start, moves, position.Score())
+	// начало партии решать не надо, а перебор без отсечений долог уже с 11 пустыми
+	samples := append([]game.Sample(nil), all[:3]...)
+	for _, sample := range all {
+		if sample.Position.Empties().Count() <= 10 {
+			samples = append(samples, sample)
+		}
+	}
+
+	labelled, err := Solver{}.Label(context.Background(), samples)
+	assert.NoError(t, err)
+	assert.Len(t, labelled, len(samples))
+	assert.Equal(t, samples[:3], labelled[:3], "больше MaxEmpties пустых клеток")
+	for i, sample := range labelled[3:] {
+		next, _ := sample.Position.Apply(sample.Move)
+		assert.Equal(t, -minimax(next, sample.Move.Color.Opponent()), sample.Score, i)
+	}
+	assert.Equal(t, samples[len(samples)-1], labelled[len(labelled)-1], "последний ход итог знает точно")
+}
+
+func TestPlayer_Move(t *testing.T) {
+	p := Wrap(greedy.New(false), 10, Exact)
+	p.SetColor(player.Green)
+	var _ game.Mover = p
+
+	// позиция, где есть из чего выбирать
+	position, color := randomPosition(rand.New(rand.NewSource(3)), 8, 8)
+	for seed := int64(4); len(position.LegalMoves(color)) < 2; seed++ {
+		if seed > 100 {
+			t.Fatal("no position with two legal moves")
+		}
+		position, color = randomPosition(rand.New(rand.NewSource(seed)), 8, 8)
+	}
+	p.SetColor(color)
+	move, err := p.Move(context.Background(), position, position.LegalMoves(color), game.Clock{})
+	assert.NoError(t, err)
+	next, _ := position.Apply(move)
+	assert.Equal(t, minimax(position, color), -minimax(next, color.Opponent()), "ход по решению")
+
+	// в начале партии ходит обёрнутый игрок
+	p.SetColor(player.Green)
+	start := game.NewPosition()
+	move, err = p.Move(context.Background(), start, start.LegalMoves(player.Green), game.Clock{})
+	assert.NoError(t, err)
+	assert.Equal(t, start.LegalMoves(player.Green)[0], move)
+}
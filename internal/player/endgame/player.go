@@ -0,0 +1,68 @@
+package endgame
+
+import (
+	"context"
+	"time"
+
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// Player ходит как обёрнутый игрок, пока пустых клеток больше Empties,
+// а дальше по точному решению. Если решение не успело за половину оставшегося
+// времени, ход делает обёрнутый игрок.
+type Player struct {
+	player.Player
+	mover   game.Mover
+	empties int
+	solver  Solver
+}
+
+// Wrap игрок p, который с empties пустых клеток и меньше переходит на решение.
+// empties больше MaxEmpties уменьшается до MaxEmpties.
+func Wrap(p player.Player, empties int, mode Mode) *Player {
+	if empties > MaxEmpties {
+		empties = MaxEmpties
+	}
+	return &Player{Player: p, mover: game.Adapt(p), empties: empties, solver: Solver{Mode: mode}}
+}
+
+func (p *Player) endgame(position game.Position) bool {
+	return position.Empties().Count() <= p.empties
+}
+
+func (p *Player) Move(ctx context.Context, position game.Position, legal []game.Move, clock game.Clock) (game.Move, error) {
+	if len(legal) > 1 && p.endgame(position) {
+		solveCtx, cancel := ctx, context.CancelFunc(func() {})
+		if clock.Remaining > 0 {
+			solveCtx, cancel = context.WithTimeout(ctx, clock.Remaining/2)
+		}
+		begin := time.Now()
+		solution, err := p.solver.Solve(solveCtx, position, p.Color())
+		cancel()
+		if err == nil {
+			return solution.Move, nil
+		}
+		clock.Remaining -= time.Since(begin)
+	}
+	return p.mover.Move(ctx, position, legal, clock)
+}
+
+func (p *Player) Step(cells []player.Color, enabled []bool, step func(string) error) {
+	position, err := game.PositionFromCells(cells)
+	if err == nil && p.endgame(position) {
+		solution, err := p.solver.Solve(context.Background(), position, p.Color())
+		if err == nil && !solution.Move.IsPass() && step(game.CellName(solution.Move.Cell, position.Size())) == nil {
+			return
+		}
+	}
+	p.Player.Step(cells, enabled, step)
+}
+
+// SetVariant сообщает правила решению и обёрнутому игроку
+func (p *Player) SetVariant(variant game.Variant) {
+	p.solver.Anti = variant.Anti
+	if v, ok := p.Player.(game.VariantPlayer); ok {
+		v.SetVariant(variant)
+	}
+}
@@ -0,0 +1,85 @@
+// Package greedy игрок, который ходит туда, где сразу переворачивает больше всего фишек,
+// а с Corners ещё и держится за углы. Не заглядывает вперёд ни на ход,
+// поэтому проигрывает любому перебору, но уже сильнее случайных ходов.
+package greedy
+
+import (
+	"sort"
+
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// Player при равном числе переворотов выбирает клетку с меньшим номером,
+// поэтому в одной позиции всегда ходит одинаково.
+// С Corners углы занимаются в первую очередь, а клетки рядом с пустым углом - в последнюю.
+type Player struct {
+	Corners bool
+	color   player.Color
+}
+
+func New(corners bool) *Player {
+	return &Player{Corners: corners}
+}
+
+// оценки углов и клеток рядом с пустым углом, перекрывают любое число переворотов
+const (
+	cornerBonus   = 1000
+	nearCornerFee = 500
+)
+
+// Step пробует клетки по убыванию оценки, пока игра не примет ход
+func (p *Player) Step(cells []player.Color, enabled []bool, step func(string) error) {
+	position, err := game.PositionFromCells(cells)
+	if err != nil {
+		return
+	}
+	type candidate struct {
+		cellN int
+		score int
+	}
+	candidates := []candidate{}
+	for i, ok := range enabled {
+		if ok {
+			candidates = append(candidates, candidate{cellN: i, score: p.score(position, i)})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	for _, c := range candidates {
+		if step(game.CellName(c.cellN, position.Size())) == nil {
+			return
+		}
+	}
+}
+
+func (p *Player) score(position game.Position, cellN int) int {
+	result := position.Flips(game.Move{Color: p.color, Cell: cellN}).Count()
+	if !p.Corners {
+		return result
+	}
+	size := position.Size()
+	row, col := cellN/size, cellN%size
+	for _, corner := range []int{0, size - 1, size * (size - 1), size*size - 1} {
+		cornerRow, cornerCol := corner/size, corner%size
+		switch {
+		case cellN == corner:
+			return result + cornerBonus
+		case abs(row-cornerRow) <= 1 && abs(col-cornerCol) <= 1 && position.Cell(corner) == player.Empty:
+			return result - nearCornerFee
+		}
+	}
+	return result
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func (p *Player) Notify(player.Result)    {}
+func (p *Player) SetColor(v player.Color) { p.color = v }
+func (p *Player) Color() player.Color     { return p.color }
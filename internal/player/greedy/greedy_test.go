@@ -0,0 +1,40 @@
+package greedy
+
+import (
+	"testing"
+
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlayer_Step(t *testing.T) {
+	position, turn, err := game.ParseSetup("-RG----- GRRR---- -------- -------- -------- -------- -------- -------- G")
+	assert.NoError(t, err)
+	enabled := make([]bool, 64)
+	for _, move := range position.LegalMoves(turn) {
+		enabled[move.Cell] = true
+	}
+	tests := map[string]struct {
+		corners bool
+		refuse  string
+		want    string
+	}{
+		"most flips":            {want: "E2"},
+		"corner":                {corners: true, want: "A1"},
+		"next when not allowed": {corners: true, refuse: "A1", want: "E2"},
+	}
+	for name, tt := range tests {
+		p := New(tt.corners)
+		p.SetColor(player.Green)
+		got := ""
+		p.Step(position.Cells(), enabled, func(s string) error {
+			if s == tt.refuse {
+				return assert.AnError
+			}
+			got = s
+			return nil
+		})
+		assert.Equal(t, tt.want, got, name)
+	}
+}
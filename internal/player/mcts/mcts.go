@@ -0,0 +1,348 @@
+// Package mcts игрок, который выбирает ход поиском по дереву методом Монте-Карло (UCT).
+// Позиции оцениваются доигрыванием до конца партии, поэтому знаний об игре не нужно.
+// Дерево сохраняется между ходами и продолжает расти с позиции после хода соперника.
+package mcts
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// DefaultPlayouts доигрываний на ход, если не заданы ни их число, ни время
+const DefaultPlayouts = 1000
+
+// DefaultExploration коэффициент исследования в UCT
+const DefaultExploration = 1.4
+
+// Rollout как выбираются ходы при доигрывании
+type Rollout int
+
+const (
+	RolloutRandom    Rollout = iota // случайный допустимый ход
+	RolloutHeuristic                // угол, если можно, иначе случайный, избегая клеток по диагонали от углов
+)
+
+func (rollout Rollout) String() string {
+	switch rollout {
+	case RolloutRandom:
+		return "random"
+	case RolloutHeuristic:
+		return "heuristic"
+	default:
+		return "undefined"
+	}
+}
+
+// Prior априорные веса ходов, например выходы сети neural.Player:
+// вес для каждой клетки поля, nil если подсказки нет
+type Prior interface {
+	Priors(position game.Position, color player.Color) []float64
+}
+
+type Player struct {
+	color       player.Color
+	playouts    int           // на ход, 0 - пока есть время
+	limit       time.Duration // на ход, 0 - не ограничено
+	exploration float64
+	rollout     Rollout
+	prior       Prior
+	random      *rand.Rand
+	anti        bool
+	root        *node // дерево с прошлого хода
+}
+
+type Option func(*Player)
+
+// WithPlayouts число доигрываний на ход
+func WithPlayouts(playouts int) Option {
+	return func(p *Player) {
+		p.playouts = playouts
+	}
+}
+
+// WithTime предел времени на доигрывания одного хода, см. game.Clock.Budget
+func WithTime(limit time.Duration) Option {
+	return func(p *Player) {
+		p.limit = limit
+	}
+}
+
+// WithExploration коэффициент исследования: чем больше, тем чаще проверяются редкие ходы
+func WithExploration(exploration float64) Option {
+	return func(p *Player) {
+		p.exploration = exploration
+	}
+}
+
+// WithRollout политика доигрывания
+func WithRollout(rollout Rollout) Option {
+	return func(p *Player) {
+		p.rollout = rollout
+	}
+}
+
+// WithPrior выбор ходов с учётом подсказки (PUCT): ходы с большим весом
+// проверяются раньше и чаще
+func WithPrior(prior Prior) Option {
+	return func(p *Player) {
+		p.prior = prior
+	}
+}
+
+// WithSeed генератор доигрываний, с одним seed поиск повторяется
+func WithSeed(seed int64) Option {
+	return func(p *Player) {
+		p.random = rand.New(rand.NewSource(seed))
+	}
+}
+
+// New игрок с DefaultPlayouts доигрываний на ход, если не заданы их число и время
+func New(opts ...Option) *Player {
+	p := &Player{exploration: DefaultExploration}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.random == nil {
+		p.random = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	if p.playouts == 0 && p.limit == 0 {
+		p.playouts = DefaultPlayouts
+	}
+	return p
+}
+
+// Move ищет ход, пока не сделаны все доигрывания, не кончилось время хода или не отменён ctx
+func (p *Player) Move(ctx context.Context, position game.Position, legal []game.Move, clock game.Clock) (game.Move, error) {
+	switch len(legal) {
+	case 0:
+		return game.Move{Color: p.color, Cell: game.Pass}, nil
+	case 1:
+		p.root = nil // ход за игрока не выбирался, дерево начнётся заново
+		return legal[0], nil
+	}
+	ctx, cancel := clock.Budget(ctx, position, p.limit)
+	defer cancel()
+	return p.Search(ctx, position, p.color), nil
+}
+
+// Step ход для игры через функцию хода: часов игры здесь нет, доигрывания
+// ограничены WithTime или их числом. Если ход не принят, дерево строится заново.
+func (p *Player) Step(cells []player.Color, enabled []bool, step func(string) error) {
+	position, err := game.PositionFromCells(cells)
+	if err != nil {
+		return
+	}
+	ctx, cancel := game.Clock{}.Budget(context.Background(), position, p.limit)
+	defer cancel()
+	move := p.Search(ctx, position, p.color)
+	if move.IsPass() || step(game.CellName(move.Cell, position.Size())) == nil {
+		return
+	}
+	p.root = nil
+	for cellN, ok := range enabled {
+		if ok && step(game.CellName(cellN, position.Size())) == nil {
+			return
+		}
+	}
+}
+
+// Search строит дерево из позиции, где ходит color, и возвращает самый проверенный ход.
+// Если в прошлом дереве есть эта позиция, поиск продолжается с неё.
+func (p *Player) Search(ctx context.Context, position game.Position, color player.Color) game.Move {
+	if !position.HasMoves(color) {
+		p.root = nil
+		return game.Move{Color: color, Cell: game.Pass}
+	}
+	root := p.root.find(position.HashTurn(color), 3)
+	if root == nil {
+		root = &node{position: position, color: color, move: game.Pass}
+	}
+	root.parent = nil
+	if root.children == nil {
+		p.expand(root) // чтобы был ход, даже если времени нет совсем
+	}
+	for i := 0; (p.playouts == 0 || i < p.playouts) && ctx.Err() == nil; i++ {
+		p.playout(root)
+	}
+	best := root.children[0]
+	for _, child := range root.children[1:] {
+		if child.visits > best.visits {
+			best = child
+		}
+	}
+	p.root = best
+	return game.Move{Color: color, Cell: best.move}
+}
+
+// node позиция в дереве поиска
+type node struct {
+	position game.Position
+	color    player.Color // чей ход в позиции
+	move     int          // ход, которым пришли в позицию
+	parent   *node
+	children []*node // nil, пока узел не раскрыт
+	prior    float64
+	visits   int
+	wins     float64 // для сделавшего move: выигрыш 1, ничья 0.5
+}
+
+// find узел с позицией hash не глубже depth ходов от n, чтобы продолжить дерево
+func (n *node) find(hash uint64, depth int) *node {
+	if n == nil {
+		return nil
+	}
+	if n.position.HashTurn(n.color) == hash {
+		return n
+	}
+	if depth == 0 {
+		return nil
+	}
+	for _, child := range n.children {
+		if found := child.find(hash, depth-1); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// playout выбирает путь по дереву, раскрывает последний узел,
+// доигрывает из него партию и учитывает результат на пути
+func (p *Player) playout(root *node) {
+	n := root
+	for len(n.children) > 0 {
+		n = p.selectChild(n)
+	}
+	if !n.position.IsTerminal() {
+		p.expand(n)
+		n = p.selectChild(n)
+	}
+	winner := p.simulate(n.position, n.color)
+	for ; n != nil; n = n.parent {
+		n.visits++
+		mover := n.color.Opponent()
+		switch winner {
+		case mover:
+			n.wins++
+		case player.Empty:
+			n.wins += 0.5
+		}
+	}
+}
+
+// expand добавляет узлы для всех ходов позиции, для пропуска - один узел
+func (p *Player) expand(n *node) {
+	legal := n.position.Legal(n.color).Cells()
+	if len(legal) == 0 {
+		n.children = []*node{{position: n.position, color: n.color.Opponent(), move: game.Pass, parent: n, prior: 1}}
+		return
+	}
+	p.random.Shuffle(len(legal), func(i, j int) { legal[i], legal[j] = legal[j], legal[i] })
+	var priors []float64
+	if p.prior != nil {
+		priors = p.prior.Priors(n.position, n.color)
+	}
+	sum := 0.
+	for _, cellN := range legal {
+		if priors != nil {
+			sum += priors[cellN]
+		}
+	}
+	n.children = make([]*node, 0, len(legal))
+	for _, cellN := range legal {
+		prior := 1 / float64(len(legal))
+		if sum > 0 {
+			prior = priors[cellN] / sum
+		}
+		next, _ := n.position.Apply(game.Move{Color: n.color, Cell: cellN})
+		n.children = append(n.children, &node{position: next, color: n.color.Opponent(), move: cellN, parent: n, prior: prior})
+	}
+}
+
+// selectChild без подсказки по UCT, сначала каждый непроверенный ход,
+// с подсказкой по PUCT, где редкие ходы с большим весом проверяются чаще
+func (p *Player) selectChild(n *node) *node {
+	var best *node
+	bestScore := math.Inf(-1)
+	for _, child := range n.children {
+		var score float64
+		switch {
+		case p.prior != nil:
+			q := 0.5
+			if child.visits > 0 {
+				q = child.wins / float64(child.visits)
+			}
+			score = q + p.exploration*child.prior*math.Sqrt(float64(n.visits))/float64(1+child.visits)
+		case child.visits == 0:
+			return child
+		default:
+			score = child.wins/float64(child.visits) + p.exploration*math.Sqrt(math.Log(float64(n.visits))/float64(child.visits))
+		}
+		if score > bestScore {
+			best, bestScore = child, score
+		}
+	}
+	return best
+}
+
+// simulate доигрывает партию из позиции, где ходит color, и возвращает победителя,
+// для ничьей player.Empty
+func (p *Player) simulate(position game.Position, color player.Color) player.Color {
+	for passed := false; ; color = color.Opponent() {
+		legal := position.Legal(color).Cells()
+		if len(legal) == 0 {
+			if passed {
+				break
+			}
+			passed = true
+			continue
+		}
+		passed = false
+		position, _ = position.Apply(game.Move{Color: color, Cell: p.pick(position, legal)})
+	}
+	score := position.Score()
+	if p.anti {
+		score = -score
+	}
+	switch {
+	case score > 0:
+		return player.Green
+	case score < 0:
+		return player.Red
+	}
+	return player.Empty
+}
+
+// pick ход доигрывания по политике
+func (p *Player) pick(position game.Position, legal []int) int {
+	if p.rollout == RolloutHeuristic {
+		size := position.Size()
+		safe := make([]int, 0, len(legal))
+		for _, cellN := range legal {
+			row, col := cellN/size, cellN%size
+			edgeRow, edgeCol := row == 0 || row == size-1, col == 0 || col == size-1
+			nearRow, nearCol := row == 1 || row == size-2, col == 1 || col == size-2
+			switch {
+			case edgeRow && edgeCol:
+				return cellN
+			case !(nearRow && nearCol):
+				safe = append(safe, cellN)
+			}
+		}
+		if len(safe) > 0 {
+			legal = safe
+		}
+	}
+	return legal[p.random.Intn(len(legal))]
+}
+
+// SetVariant в антиреверси выигрывает тот, у кого меньше фишек
+func (p *Player) SetVariant(variant game.Variant) { p.anti = variant.Anti }
+
+func (p *Player) Notify(player.Result)    { p.root = nil }
+func (p *Player) SetColor(v player.Color) { p.color = v }
+func (p *Player) Color() player.Color     { return p.color }
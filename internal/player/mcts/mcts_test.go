@@ -0,0 +1,79 @@
+package mcts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player"
+	"github.com/slonegd-go/reversi/internal/player/random"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlayer_Search_win(t *testing.T) {
+	// A1 - единственный ход, он забирает все фишки красного
+	position, color, err := game.ParseSetup("-RRRRRRG -------- -------- -------- -------- -------- -------- -------- G")
+	assert.NoError(t, err)
+	for _, rollout := range []Rollout{RolloutRandom, RolloutHeuristic} {
+		p := New(WithPlayouts(200), WithSeed(1), WithRollout(rollout))
+		assert.Equal(t, game.Move{Color: player.Green, Cell: 0}, p.Search(context.Background(), position, color), rollout)
+	}
+}
+
+type cornerPrior struct{}
+
+func (cornerPrior) Priors(position game.Position, _ player.Color) []float64 {
+	result := make([]float64, position.Size()*position.Size())
+	result[len(result)-1] = 1
+	return result
+}
+
+func TestPlayer_Search_prior(t *testing.T) {
+	position, color, err := game.ParseSetup("-------- -------- -------- -------- -------- -------- -------- GRRRRRR- G")
+	assert.NoError(t, err)
+	p := New(WithPlayouts(50), WithSeed(1), WithPrior(cornerPrior{}))
+	assert.Equal(t, 63, p.Search(context.Background(), position, color).Cell)
+	assert.Equal(t, 50, p.root.parent.visits)
+	assert.True(t, p.root.visits > 25, "подсказанный ход проверяется чаще остальных: %d", p.root.visits)
+}
+
+func TestPlayer_reuse(t *testing.T) {
+	p := New(WithPlayouts(100), WithSeed(1))
+	position := game.NewPosition()
+	move := p.Search(context.Background(), position, player.Green)
+	position, _ = position.Apply(move)
+	reply := p.root.children[0]
+	position, _ = position.Apply(game.Move{Color: player.Red, Cell: reply.move})
+	visits := reply.visits
+
+	p.Search(context.Background(), position, player.Green)
+	assert.Nil(t, p.root.parent.parent, "корень отцеплен от старого дерева")
+	assert.Equal(t, visits+100, p.root.parent.visits, "поиск продолжен с ответа соперника")
+}
+
+func TestPlayer_Move_time(t *testing.T) {
+	var p game.Mover = New(WithTime(50 * time.Millisecond))
+	p.SetColor(player.Green)
+	position := game.NewPosition()
+	begin := time.Now()
+	move, err := p.Move(context.Background(), position, position.LegalMoves(player.Green), game.Clock{})
+	assert.NoError(t, err)
+	assert.True(t, position.Legal(player.Green).Has(move.Cell))
+	assert.True(t, time.Since(begin) < time.Second, time.Since(begin))
+}
+
+func TestPlayer_beatsRandom(t *testing.T) {
+	wins := 0
+	for i := int64(0); i < 4; i++ {
+		p1, p2 := player.Player(New(WithPlayouts(300), WithSeed(i))), player.Player(random.New(i))
+		if i%2 == 1 {
+			p1, p2 = p2, p1
+		}
+		result := game.New(p1, p2, game.WithSize(6)).Start(context.Background())
+		if result.Winner != player.Empty && (result.Winner == player.Green) == (i%2 == 0) {
+			wins++
+		}
+	}
+	assert.True(t, wins >= 3, wins)
+}
@@ -2,7 +2,6 @@ package neural
 
 import (
 	"encoding/gob"
-	"fmt"
 	"log"
 	"math/rand"
 	"os"
@@ -11,11 +10,13 @@ import (
 
 	"github.com/patrikeh/go-deep"
 	"github.com/patrikeh/go-deep/training"
+	"github.com/slonegd-go/reversi/internal/game"
 	"github.com/slonegd-go/reversi/internal/player"
 )
 
 type Player struct {
 	color    player.Color
+	size     int          // сторона поля
 	neural   *deep.Neural // обучение во время игры
 	persist  persist
 	inputs   []float64
@@ -31,6 +32,7 @@ type persist struct {
 	WinCount, LoseCount int
 	EpochCount          int
 	LastFilename        string
+	Size                int // сторона поля, 0 в старых файлах означает 8
 }
 
 type step struct {
@@ -99,10 +101,19 @@ func (p *Player) CopyToFilename(path string, filename string, changeWeight ...bo
 
 var weightFunc = deep.NewNormal(1, 0)
 
+// New игрок для поля 8x8
 func New(path, filename string) *Player {
+	return NewSize(path, filename, game.DefaultSize)
+}
+
+// NewSize игрок для поля size x size.
+// На каждый свой ход сеть получает позицию в size входов, по одному на строку,
+// выходы соответствуют всем клеткам, кроме четырёх центральных.
+func NewSize(path, filename string, size int) *Player {
+	inputs, outputs := inputsCount(size), size*size-4
 	neural := deep.NewNeural(&deep.Config{
-		Inputs:     240,
-		Layout:     []int{360, 480, 360, 240, 120, 60},
+		Inputs:     inputs,
+		Layout:     []int{inputs * 3 / 2, inputs * 2, inputs * 3 / 2, inputs, outputs * 2, outputs},
 		Activation: deep.ActivationSigmoid,
 		Mode:       deep.ModeRegression,
 		Weight:     weightFunc,
@@ -110,82 +121,96 @@ func New(path, filename string) *Player {
 	})
 	persist := persist{
 		Weights: neural.Weights(),
+		Size:    size,
 	}
 
 	file, err := os.Open(filepath.Join(path, filename))
 	if err == nil {
 		defer file.Close()
-		gob.NewDecoder(file).Decode(&persist)
-		neural.ApplyWeights(persist.Weights)
+		loaded := persist
+		gob.NewDecoder(file).Decode(&loaded)
+		if loaded.Size == 0 {
+			loaded.Size = game.DefaultSize
+		}
+		if loaded.Size == size {
+			persist = loaded
+			neural.ApplyWeights(persist.Weights)
+		} else {
+			log.Printf("%s: saved for board size %d, got %d", filename, loaded.Size, size)
+		}
 	} else {
 		log.Printf(err.Error())
 	}
 
 	return &Player{
+		size:     size,
 		neural:   neural,
 		persist:  persist,
-		inputs:   make([]float64, 240),
+		inputs:   make([]float64, inputs),
 		trainer:  training.NewTrainer(training.NewSGD(0.005, 0.5, 1e-6, true), 0),
 		path:     path,
 		filename: filepath.Join(path, filename),
 	}
 }
 
+// Step пробует клетки по убыванию предсказания, пока игра не примет ход.
+// Если не принят ни один, возвращается без хода.
 func (p *Player) Step(colors []player.Color, enabledCells []bool, stepFunc func(string) error) {
 
 	p.updateInputs(colors)
 
-	for {
-		// time.Sleep(200 * time.Millisecond)
-		// log.Printf("inputs: %+v", p.inputs)
-		outputs := p.neural.Predict(p.inputs)
-
-		predict := []output{}
-		for i, f64 := range outputs {
-			n := cellN(i)
-			if !enabledCells[n] {
-				outputs[i] = 0
-				f64 = 0
-			}
-
-			predict = append(predict, output{
-				i:      i,
-				cell:   cell(n),
-				weight: abs(f64),
-			})
+	// time.Sleep(200 * time.Millisecond)
+	// log.Printf("inputs: %+v", p.inputs)
+	outputs := p.neural.Predict(p.inputs)
 
+	predict := []output{}
+	for i, f64 := range outputs {
+		n := cellN(i, p.size)
+		if !enabledCells[n] {
+			outputs[i] = 0
+			f64 = 0
 		}
-		sort.Slice(predict, func(i, j int) bool {
-			return predict[i].weight > predict[j].weight // по убыванию
+
+		predict = append(predict, output{
+			i:      i,
+			cell:   cell(n, p.size),
+			weight: abs(f64),
 		})
-		// log.Printf("predict: %+v", predict)
-		log.Printf("predict:\n\t%+v,\n\t%+v,\n\t%+v,\n\t%+v,\n\t%+v",
-			predict[0], predict[1], predict[2], predict[3], predict[4])
 
-		err := stepFunc(predict[0].cell)
-		if err != nil {
+	}
+	sort.Slice(predict, func(i, j int) bool {
+		return predict[i].weight > predict[j].weight // по убыванию
+	})
+	// log.Printf("predict: %+v", predict)
+	log.Printf("predict:\n\t%+v,\n\t%+v,\n\t%+v,\n\t%+v,\n\t%+v",
+		predict[0], predict[1], predict[2], predict[3], predict[4])
+
+	for _, candidate := range predict {
+		if err := stepFunc(candidate.cell); err != nil {
 			continue
 		}
-
 		p.steps = append(p.steps, step{
 			inputs:      p.inputs,
 			outputs:     outputs,
-			outputIndex: predict[0].i,
+			outputIndex: candidate.i,
 		})
-		break
+		return
 	}
 }
 
 func (p *Player) Notify(result player.Result) {
-	p.inputs = make([]float64, 240)
+	p.inputs = make([]float64, len(p.inputs))
 	p.index = 0
 
 	k := 2. // увеличение удачных шагов
-	if result == player.Lose {
+	switch result {
+	case player.Lose:
 		p.persist.LoseCount++
 		k = 1 / k // если проиграли, то опустить неудачные шаги
-	} else {
+	case player.Win:
 		p.persist.WinCount++
+	case player.Draw:
+		k = 1 // ничья не учит ни в какую сторону
 	}
 
 	examples := make([]training.Example, 0, len(p.steps))
@@ -200,45 +225,109 @@ func (p *Player) Notify(result player.Result) {
 
 	p.trainer.Train(p.neural, examples, nil, 1)
 
+	if err := p.Save(); err != nil {
+		log.Printf(err.Error())
+	}
+}
+
+// Learn обучает на ходах одной партии, например из базы сыгранных людьми.
+// Ходы в партиях с большим итоговым счётом усиливаются сильнее.
+// Примеры с поля другого размера пропускаются.
+func (p *Player) Learn(samples []game.Sample) {
+	examples := make([]training.Example, 0, len(samples))
+	for _, color := range []player.Color{player.Green, player.Red} {
+		inputs := make([]float64, len(p.inputs))
+		index := 0
+		for _, sample := range samples {
+			if sample.Move.Color != color || sample.Position.Size() != p.size {
+				continue
+			}
+			i := outputIndex(sample.Move.Cell, p.size)
+			if i < 0 {
+				continue
+			}
+			index = encode(inputs, index, sample.Position.Cells(), color)
+			outputs := make([]float64, p.size*p.size-4)
+			outputs[i] = 0.5 + float64(sample.Score)/128
+			examples = append(examples, training.Example{
+				Input:    append([]float64(nil), inputs...),
+				Response: outputs,
+			})
+		}
+	}
+	p.trainer.Train(p.neural, examples, nil, 1)
+}
+
+// Priors предсказание сети для хода color в позиции без истории ходов:
+// вес каждой клетки поля, для центральных клеток 0. Состояние игрока не меняется.
+// Для поля другого размера возвращается nil.
+func (p *Player) Priors(position game.Position, color player.Color) []float64 {
+	if position.Size() != p.size {
+		return nil
+	}
+	inputs := make([]float64, len(p.inputs))
+	encode(inputs, 0, position.Cells(), color)
+	result := make([]float64, p.size*p.size)
+	for i, f64 := range p.neural.Predict(inputs) {
+		result[cellN(i, p.size)] = abs(f64)
+	}
+	return result
+}
+
+// Save сохраняет веса и статистику в файл игрока
+func (p *Player) Save() error {
 	p.persist.Weights = p.neural.Weights()
 	if err := os.MkdirAll(p.path, os.ModePerm); err != nil {
-		log.Printf(err.Error())
-		return
+		return err
 	}
 	file, err := os.Create(p.filename)
-	if err == nil {
-		gob.NewEncoder(file).Encode(p.persist)
-	} else {
-		log.Printf(err.Error())
+	if err != nil {
+		return err
 	}
+	defer file.Close()
+	return gob.NewEncoder(file).Encode(p.persist)
 }
 
 func (player *Player) SetColor(v player.Color) { player.color = v }
 func (player *Player) Color() player.Color     { return player.color }
 
 func (p *Player) updateInputs(colors []player.Color) {
-	uints := [8]uint16{}
+	p.index = encode(p.inputs, p.index, colors, p.color)
+}
+
+// encode дописывает позицию с точки зрения own во входы с индекса index,
+// возвращает следующий индекс. Когда входы закончились, позиция не пишется.
+func encode(inputs []float64, index int, colors []player.Color, own player.Color) int {
+	size := 0
+	for size*size < len(colors) {
+		size++
+	}
+	if index+size > len(inputs) {
+		return index
+	}
+	rows := make([]uint32, size)
 	for i, color := range colors {
-		offset := (i % 8) * 2
-		index := i / 8
+		offset := (i % size) * 2
+		row := i / size
 		if color == player.Empty {
-			uints[index] = uints[index] | 0b11<<offset
+			rows[row] = rows[row] | 0b11<<offset
 			continue
 		}
-		if color == p.color {
-			uints[index] = uints[index] | 0b01<<offset
+		if color == own {
+			rows[row] = rows[row] | 0b01<<offset
 			continue
 		}
-		uints[index] = uints[index] | 0b10<<offset
+		rows[row] = rows[row] | 0b10<<offset
 	}
-	for _, ui := range uints {
+	for _, row := range rows {
 		f := 0.
-		if ui != 0 {
-			f = 1. / float64(ui)
+		if row != 0 {
+			f = 1. / float64(row)
 		}
-		p.inputs[p.index] = f
-		p.index++
+		inputs[index] = f
+		index++
 	}
+	return index
 }
 
 type output struct {
@@ -254,18 +343,42 @@ func abs(v float64) float64 {
 	return -v
 }
 
-func cellN(i int) int {
-	if i > 24+3-1 { // D4, E4 пропускаем
+// inputsCount входов сети: по строке поля на каждый свой ход
+func inputsCount(size int) int {
+	return size * (size*size - 4) / 2
+}
+
+// center левая верхняя из четырёх центральных клеток
+func center(size int) int {
+	return (size/2-1)*size + size/2 - 1
+}
+
+// cellN клетка по номеру выхода сети
+func cellN(i int, size int) int {
+	center := center(size)
+	if i >= center { // D4, E4 пропускаем
 		i += 2
 	}
-	if i > 32+3-1 { // D5, E5 пропускаем
+	if i >= center+size { // D5, E5 пропускаем
 		i += 2
 	}
 	return i
 }
 
-func cell(i int) string {
-	letter := rune('A' + i%8)
-	index := rune('1' + i/8)
-	return fmt.Sprintf("%c%c", letter, index)
+// outputIndex обратное к cellN, для центральных клеток -1
+func outputIndex(cellN int, size int) int {
+	center := center(size)
+	switch {
+	case cellN >= center+size+2:
+		return cellN - 4
+	case cellN >= center+2 && cellN < center+size:
+		return cellN - 2
+	case cellN < center:
+		return cellN
+	}
+	return -1
+}
+
+func cell(i int, size int) string {
+	return game.CellName(i, size)
 }
@@ -3,6 +3,7 @@ package neural
 import (
 	"testing"
 
+	"github.com/slonegd-go/reversi/internal/game"
 	"github.com/slonegd-go/reversi/internal/player"
 	"github.com/stretchr/testify/assert"
 )
@@ -18,17 +19,17 @@ func TestPlayer_updateInputs(t *testing.T) {
 			name:   "our",
 			colors: append([]player.Color{g}, generateColors(e, 63)...),
 			color:  g,
-			want:   append([]float64{0xFFFFFFFD, 0xFFFFFFFF, 0xFFFFFFFF, 0xFFFFFFFF}, generateFloats(0, 116)...),
+			want:   append(append([]float64{1. / 0xFFFD}, generateFloats(1./0xFFFF, 7)...), generateFloats(0, 232)...),
 		},
 		{
 			name:   "not our",
 			colors: append([]player.Color{r}, generateColors(e, 63)...),
 			color:  g,
-			want:   append([]float64{0xFFFFFFFE, 0xFFFFFFF, 0xFFFFFFFF, 0xFFFFFFFF}, generateFloats(0, 116)...),
+			want:   append(append([]float64{1. / 0xFFFE}, generateFloats(1./0xFFFF, 7)...), generateFloats(0, 232)...),
 		},
 	}
 	for _, tt := range tests {
-		p := New("")
+		p := New("", "")
 		p.SetColor(tt.color)
 		p.updateInputs(tt.colors)
 		assert.Equal(t, tt.want, p.inputs, tt.name)
@@ -48,7 +49,18 @@ func Test_cell(t *testing.T) {
 		"F5": {i: 32 + 4 - 1 - 2, want: "F5"},
 	}
 	for name, tt := range tests {
-		assert.Equal(t, tt.want, cell(tt.i), name)
+		assert.Equal(t, tt.want, cell(cellN(tt.i, 8), 8), name)
+	}
+}
+
+func Test_outputIndex(t *testing.T) {
+	for size := game.MinSize; size <= game.MaxSize; size += 2 {
+		for i := 0; i < size*size-4; i++ {
+			assert.Equal(t, i, outputIndex(cellN(i, size), size), "size %d, output %d", size, i)
+		}
+	}
+	for _, center := range []int{27, 28, 35, 36} {
+		assert.Equal(t, -1, outputIndex(center, 8), center)
 	}
 }
 
@@ -79,3 +91,32 @@ func generateFloats(f float64, count int) []float64 {
 	}
 	return result
 }
+
+func TestPlayer_Priors(t *testing.T) {
+	p := NewSize("", "", 6)
+	priors := p.Priors(mustPosition(6), player.Green)
+	assert.Len(t, priors, 36)
+	for _, cellN := range []int{14, 15, 20, 21} {
+		assert.Zero(t, priors[cellN], "центр")
+	}
+	assert.Empty(t, p.inputs[0], "входы игрока не меняются")
+	assert.Nil(t, p.Priors(game.NewPosition(), player.Green), "другой размер")
+}
+
+func TestPlayer_Learn_size(t *testing.T) {
+	p := NewSize("", "", 6)
+	start := game.NewPosition()
+	e6, _ := game.ParseCell("E6", 8) // за пределами выходов сети 6x6
+	move := game.Move{Color: player.Green, Cell: e6}
+	assert.NotPanics(t, func() {
+		p.Learn([]game.Sample{{Position: start, Move: move, Score: 64}})
+	}, "пример с поля 8x8 пропускается")
+}
+
+func mustPosition(size int) game.Position {
+	position, err := game.NewPositionSize(size)
+	if err != nil {
+		panic(err)
+	}
+	return position
+}
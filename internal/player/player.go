@@ -21,23 +21,48 @@ var (
 )
 
 func (c Color) String() string {
+	switch c {
+	case Green:
+		return green(c.Name())
+	case Red:
+		return red(c.Name())
+	default:
+		return c.Name()
+	}
+}
+
+// Name название цвета без раскраски, для текста ошибок
+func (c Color) Name() string {
 	switch c {
 	case Empty:
 		return "empty"
 	case Green:
-		return green("green")
+		return "green"
 	case Red:
-		return red("red")
+		return "red"
 	default:
 		return fmt.Sprintf("undefined(%d)", c)
 	}
 }
 
+// Opponent цвет соперника: для зелёных красные, для остальных зелёные
+func (c Color) Opponent() Color {
+	if c == Green {
+		return Red
+	}
+	return Green
+}
+
 const (
 	Lose Result = iota
 	Win
+	Draw
 )
 
+// Undo команда, которую можно передать в функцию хода вместо клетки,
+// чтобы отменить свой последний ход
+const Undo = "undo"
+
 type Player interface {
 	// второй слайс доступности ячеек
 	// в функцию надо передать код ячейки
@@ -0,0 +1,48 @@
+// Package random игрок, который ходит в случайную допустимую клетку.
+// Это нижняя планка силы: игрок, который не обыгрывает его почти всегда,
+// ничему не научился.
+package random
+
+import (
+	"math/rand"
+
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+type Player struct {
+	color  player.Color
+	random *rand.Rand
+}
+
+// New игрок со своим генератором, с одним seed ходы повторяются
+func New(seed int64) *Player {
+	return &Player{random: rand.New(rand.NewSource(seed))}
+}
+
+// Step выбирает допустимую клетку с равной вероятностью.
+// Если игра её не приняла, пробует остальные в случайном порядке.
+func (p *Player) Step(cells []player.Color, enabled []bool, step func(string) error) {
+	size := 0
+	for size*size < len(cells) {
+		size++
+	}
+	candidates := []int{}
+	for i, ok := range enabled {
+		if ok {
+			candidates = append(candidates, i)
+		}
+	}
+	p.random.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	for _, cellN := range candidates {
+		if step(game.CellName(cellN, size)) == nil {
+			return
+		}
+	}
+}
+
+func (p *Player) Notify(player.Result)    {}
+func (p *Player) SetColor(v player.Color) { p.color = v }
+func (p *Player) Color() player.Color     { return p.color }
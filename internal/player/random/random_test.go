@@ -0,0 +1,20 @@
+package random
+
+import (
+	"context"
+	"testing"
+
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player/greedy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlayer_seed(t *testing.T) {
+	play := func(seed int64) game.Result {
+		return game.New(New(seed), greedy.New(true), game.WithSize(6)).Start(context.Background())
+	}
+	first := play(1)
+	assert.Equal(t, game.Normal, first.Reason)
+	assert.Equal(t, first, play(1), "с одним seed партия повторяется")
+	assert.NotEqual(t, first.Moves, play(2).Moves)
+}
@@ -0,0 +1,77 @@
+package search
+
+import (
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// WinScore оценка выигранной позиции без учёта разницы фишек.
+// Оценки Evaluator должны быть меньше по модулю, чтобы выигрыш был лучше любой из них.
+const WinScore = 1 << 20
+
+// Evaluator оценка позиции с точки зрения игрока color: чем больше, тем лучше для него
+type Evaluator interface {
+	Evaluate(position game.Position, color player.Color) int
+}
+
+// EvaluatorFunc функция как Evaluator
+type EvaluatorFunc func(position game.Position, color player.Color) int
+
+func (f EvaluatorFunc) Evaluate(position game.Position, color player.Color) int {
+	return f(position, color)
+}
+
+// Material разница фишек
+type Material struct{}
+
+func (Material) Evaluate(position game.Position, color player.Color) int {
+	return position.Count(color) - position.Count(color.Opponent())
+}
+
+// Positional веса занятых клеток и подвижность: углы ценятся,
+// клетки рядом с углами - нет, а допустимый ход стоит как клетка на краю
+type Positional struct{}
+
+const mobilityWeight = 10
+
+func (Positional) Evaluate(position game.Position, color player.Color) int {
+	table := weights[position.Size()]
+	score := 0
+	for _, cellN := range position.Discs(color).Cells() {
+		score += table[cellN]
+	}
+	for _, cellN := range position.Discs(color.Opponent()).Cells() {
+		score -= table[cellN]
+	}
+	mobility := position.Legal(color).Count() - position.Legal(color.Opponent()).Count()
+	return score + mobilityWeight*mobility
+}
+
+// weights веса клеток для каждого размера поля
+var weights = func() [game.MaxSize + 1][]int {
+	result := [game.MaxSize + 1][]int{}
+	for size := game.MinSize; size <= game.MaxSize; size += 2 {
+		table := make([]int, size*size)
+		for cellN := range table {
+			table[cellN] = weight(cellN/size, cellN%size, size)
+		}
+		result[size] = table
+	}
+	return result
+}()
+
+func weight(row, col, size int) int {
+	edge := func(i int) bool { return i == 0 || i == size-1 }
+	nearEdge := func(i int) bool { return i == 1 || i == size-2 }
+	switch {
+	case edge(row) && edge(col):
+		return 100 // угол
+	case nearEdge(row) && nearEdge(col):
+		return -50 // по диагонали от угла
+	case edge(row) && nearEdge(col), nearEdge(row) && edge(col):
+		return -20 // на краю рядом с углом
+	case edge(row) || edge(col):
+		return 10
+	}
+	return 1
+}
@@ -0,0 +1,219 @@
+// Package search игрок, который перебирает ходы негамаксом с альфа-бета отсечением.
+// Глубина растёт по одному ходу, пока не достигнет предела или не кончится время,
+// и лучший ход прошлой глубины проверяется первым.
+package search
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// DefaultDepth глубина перебора, если не заданы ни глубина, ни время
+const DefaultDepth = 6
+
+type Player struct {
+	color     player.Color
+	depth     int           // 0 - до конца партии или пока есть время
+	limit     time.Duration // на ход, 0 - не ограничено
+	evaluator Evaluator
+	anti      bool
+}
+
+type Option func(*Player)
+
+// WithDepth предел глубины перебора в полуходах
+func WithDepth(depth int) Option {
+	return func(p *Player) {
+		p.depth = depth
+	}
+}
+
+// WithTime предел времени на перебор одного хода, с часами игры его может стать меньше
+func WithTime(limit time.Duration) Option {
+	return func(p *Player) {
+		p.limit = limit
+	}
+}
+
+// WithEvaluator оценка позиций на пределе глубины, по умолчанию Positional
+func WithEvaluator(evaluator Evaluator) Option {
+	return func(p *Player) {
+		p.evaluator = evaluator
+	}
+}
+
+// New игрок с глубиной DefaultDepth, если не заданы глубина и время
+func New(opts ...Option) *Player {
+	p := &Player{evaluator: Positional{}}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.depth == 0 && p.limit == 0 {
+		p.depth = DefaultDepth
+	}
+	return p
+}
+
+// Move ищет ход, пока не пройдена вся глубина, не кончилось время хода или не отменён ctx
+func (p *Player) Move(ctx context.Context, position game.Position, legal []game.Move, clock game.Clock) (game.Move, error) {
+	switch len(legal) {
+	case 0:
+		return game.Move{Color: p.color, Cell: game.Pass}, nil
+	case 1:
+		return legal[0], nil
+	}
+	ctx, cancel := clock.Budget(ctx, position, p.limit)
+	defer cancel()
+	move, _, _ := p.Search(ctx, position, p.color)
+	return move, nil
+}
+
+// Step ход для игры через функцию хода: часов игры здесь нет, перебор ограничен
+// только WithTime или глубиной
+func (p *Player) Step(cells []player.Color, enabled []bool, step func(string) error) {
+	position, err := game.PositionFromCells(cells)
+	if err != nil {
+		return
+	}
+	ctx, cancel := game.Clock{}.Budget(context.Background(), position, p.limit)
+	defer cancel()
+	move, _, _ := p.Search(ctx, position, p.color)
+	if move.IsPass() || step(game.CellName(move.Cell, position.Size())) == nil {
+		return
+	}
+	for cellN, ok := range enabled {
+		if ok && step(game.CellName(cellN, position.Size())) == nil {
+			return
+		}
+	}
+}
+
+// Search ищет лучший ход color с итеративным углублением до отмены ctx.
+// Возвращает ход, его оценку и глубину последнего законченного перебора.
+// Если не закончен даже перебор на один ход, возвращается первый допустимый.
+func (p *Player) Search(ctx context.Context, position game.Position, color player.Color) (move game.Move, score int, depth int) {
+	legal := position.Legal(color)
+	if legal.IsZero() {
+		return game.Move{Color: color, Cell: game.Pass}, 0, 0
+	}
+	s := &searcher{ctx: ctx, evaluator: p.evaluator, anti: p.anti, best: map[uint64]int{}}
+	move = game.Move{Color: color, Cell: legal.First()}
+	empties := position.Empties().Count()
+	for d := 1; d <= empties && (p.depth == 0 || d <= p.depth); d++ {
+		cellN, value := s.root(position, color, d)
+		if s.aborted {
+			break
+		}
+		move.Cell, score, depth = cellN, value, d
+		if score >= WinScore || score <= -WinScore {
+			break // исход партии уже известен
+		}
+	}
+	return move, score, depth
+}
+
+// searcher состояние одного поиска
+type searcher struct {
+	ctx       context.Context
+	evaluator Evaluator
+	anti      bool
+	best      map[uint64]int // лучший ход в позиции с прошлых глубин, для порядка ходов
+	nodes     int
+	aborted   bool
+}
+
+func (s *searcher) root(position game.Position, color player.Color, depth int) (int, int) {
+	alpha := -2 * WinScore
+	best := -1
+	for _, cellN := range s.order(position, color, position.Legal(color)) {
+		next, _ := position.Apply(game.Move{Color: color, Cell: cellN})
+		score := -s.negamax(next, color.Opponent(), depth-1, -2*WinScore, -alpha)
+		if s.aborted {
+			return best, alpha
+		}
+		if best < 0 || score > alpha {
+			best, alpha = cellN, score
+		}
+	}
+	s.best[position.HashTurn(color)] = best
+	return best, alpha
+}
+
+// negamax оценка позиции для color с окном (alpha, beta)
+func (s *searcher) negamax(position game.Position, color player.Color, depth, alpha, beta int) int {
+	s.nodes++
+	if s.nodes%1024 == 0 && s.ctx.Err() != nil {
+		s.aborted = true
+	}
+	if s.aborted {
+		return 0
+	}
+	legal := position.Legal(color)
+	if legal.IsZero() {
+		if !position.HasMoves(color.Opponent()) {
+			return s.final(position, color)
+		}
+		return -s.negamax(position, color.Opponent(), depth, -beta, -alpha) // пропуск не тратит глубину
+	}
+	if depth == 0 {
+		return s.evaluator.Evaluate(position, color)
+	}
+	best, bestCell := -2*WinScore, -1
+	for _, cellN := range s.order(position, color, legal) {
+		next, _ := position.Apply(game.Move{Color: color, Cell: cellN})
+		score := -s.negamax(next, color.Opponent(), depth-1, -beta, -alpha)
+		if score > best {
+			best, bestCell = score, cellN
+		}
+		if score > alpha {
+			alpha = score
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+	s.best[position.HashTurn(color)] = bestCell
+	return best
+}
+
+// final оценка законченной партии: выигрыш лучше любой оценки позиции,
+// а среди выигрышей лучше тот, где больше разница фишек
+func (s *searcher) final(position game.Position, color player.Color) int {
+	diff := position.Count(color) - position.Count(color.Opponent())
+	if s.anti {
+		diff = -diff
+	}
+	switch {
+	case diff > 0:
+		return WinScore + diff
+	case diff < 0:
+		return -WinScore + diff
+	}
+	return 0
+}
+
+// order ходы по порядку проверки: сначала лучший с прошлой глубины, потом по весу клетки
+func (s *searcher) order(position game.Position, color player.Color, legal game.Bitboard) []int {
+	cells := legal.Cells()
+	table := weights[position.Size()]
+	first, ok := s.best[position.HashTurn(color)]
+	sort.SliceStable(cells, func(i, j int) bool {
+		if ok && (cells[i] == first) != (cells[j] == first) {
+			return cells[i] == first
+		}
+		return table[cells[i]] > table[cells[j]]
+	})
+	return cells
+}
+
+// SetVariant в антиреверси выигрывает тот, у кого меньше фишек.
+// Оценка позиции о правилах не знает, это учитывается только в конце партии.
+func (p *Player) SetVariant(variant game.Variant) { p.anti = variant.Anti }
+
+func (p *Player) Notify(player.Result)    {}
+func (p *Player) SetColor(v player.Color) { p.color = v }
+func (p *Player) Color() player.Color     { return p.color }
@@ -0,0 +1,103 @@
+package search
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player"
+	"github.com/slonegd-go/reversi/internal/player/greedy"
+	"github.com/stretchr/testify/assert"
+)
+
+// minimax перебор без отсечений для сравнения
+func minimax(evaluator Evaluator, position game.Position, color player.Color, depth int) int {
+	legal := position.LegalMoves(color)
+	if len(legal) == 0 {
+		if !position.HasMoves(color.Opponent()) {
+			return (&searcher{}).final(position, color)
+		}
+		return -minimax(evaluator, position, color.Opponent(), depth)
+	}
+	if depth == 0 {
+		return evaluator.Evaluate(position, color)
+	}
+	best := -2 * WinScore
+	for _, move := range legal {
+		next, _ := position.Apply(move)
+		if score := -minimax(evaluator, next, color.Opponent(), depth-1); score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+// randomPosition позиция после moves случайных ходов и чей в ней ход
+func randomPosition(rnd *rand.Rand, size, moves int) (game.Position, player.Color) {
+	position, _ := game.NewPositionSize(size)
+	color := player.Green
+	for i := 0; i < moves && !position.IsTerminal(); i++ {
+		if legal := position.LegalMoves(color); len(legal) > 0 {
+			position, _ = position.Apply(legal[rnd.Intn(len(legal))])
+		}
+		color = color.Opponent()
+	}
+	return position, color
+}
+
+func TestPlayer_Search_minimax(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 30; i++ {
+		size := []int{4, 6, 8}[i%3]
+		position, color := randomPosition(rnd, size, rnd.Intn(size*size-4))
+		if position.IsTerminal() {
+			continue
+		}
+		for _, evaluator := range []Evaluator{Material{}, Positional{}} {
+			p := New(WithDepth(3), WithEvaluator(evaluator))
+			move, score, depth := p.Search(context.Background(), position, color)
+			if depth < 3 {
+				assert.Equal(t, minimax(evaluator, position, color, depth), score, "решено раньше: %s", game.FormatSetup(position, color))
+				continue
+			}
+			assert.Equal(t, minimax(evaluator, position, color, 3), score, game.FormatSetup(position, color))
+			if !move.IsPass() {
+				next, _ := position.Apply(move)
+				assert.Equal(t, score, -minimax(evaluator, next, color.Opponent(), 2), "ход с этой оценкой")
+			}
+		}
+	}
+}
+
+func TestPlayer_Search_win(t *testing.T) {
+	// A1 забирает все фишки красного
+	position, color, err := game.ParseSetup("-RRRRRRG -------- -------- -------- -------- -------- -------- -------- G")
+	assert.NoError(t, err)
+	move, score, _ := New(WithDepth(4)).Search(context.Background(), position, color)
+	assert.Equal(t, game.Move{Color: player.Green, Cell: 0}, move)
+	assert.Equal(t, WinScore+8, score)
+}
+
+func TestPlayer_Move_time(t *testing.T) {
+	var p game.Mover = New(WithTime(50 * time.Millisecond))
+	p.SetColor(player.Green)
+	position := game.NewPosition()
+	begin := time.Now()
+	move, err := p.Move(context.Background(), position, position.LegalMoves(player.Green), game.Clock{})
+	assert.NoError(t, err)
+	assert.True(t, position.Legal(player.Green).Has(move.Cell))
+	assert.True(t, time.Since(begin) < time.Second, time.Since(begin))
+}
+
+func TestPlayer_beatsGreedy(t *testing.T) {
+	for _, color := range []player.Color{player.Green, player.Red} {
+		p1, p2 := player.Player(New(WithDepth(4))), player.Player(greedy.New(true))
+		if color == player.Red {
+			p1, p2 = p2, p1
+		}
+		result := game.New(p1, p2, game.WithSize(6)).Start(context.Background())
+		assert.Equal(t, color, result.Winner, result)
+	}
+}
@@ -0,0 +1,235 @@
+// Package wthor читает базы партий Французской федерации отелло (WTHOR):
+// партии .wtb, игроков .jou и турниры .trn.
+//
+// Все файлы начинаются с заголовка в 16 байт, за которым идут записи
+// фиксированной длины. Числа записаны в little endian, строки в Latin-1.
+package wthor
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player"
+)
+
+// Size сторона поля партий базы
+const Size = 8
+
+const (
+	headerSize     = 16
+	gameSize       = 68
+	playerSize     = 20
+	tournamentSize = 26
+	movesCount     = 60
+)
+
+type Header struct {
+	Century, Year, Month, Day int // дата создания файла
+	Games                     int // число партий в .wtb
+	Records                   int // число записей в .jou и .trn
+	GamesYear                 int // год партий
+	BoardSize                 int // 0 или 8 для поля 8x8
+	Solitaire                 bool
+	Depth                     int // глубина расчёта теоретического счёта
+}
+
+// Game партия базы. Чёрные играют зелёными фишками, белые - красными.
+type Game struct {
+	Tournament  int // номер турнира в .trn
+	Black       int // номер игрока в .jou
+	White       int
+	Score       int         // фишек у чёрных в конце партии
+	Theoretical int         // фишек у чёрных при идеальной игре с глубины Header.Depth
+	Moves       []game.Move // вместе с пропусками
+}
+
+// Start начальная позиция партий базы: чёрные на E4 и D5, как в игре
+func Start() game.Position {
+	return game.NewPosition()
+}
+
+// Samples примеры для обучения по всем ходам партии
+func (g Game) Samples() []game.Sample {
+	return game.Samples(Start(), g.Moves, 2*g.Score-64)
+}
+
+func readHeader(r io.Reader) (Header, error) {
+	var raw [headerSize]byte
+	if _, err := io.ReadFull(r, raw[:]); err != nil {
+		return Header{}, fmt.Errorf("header: %w", err)
+	}
+	return Header{
+		Century:   int(raw[0]),
+		Year:      int(raw[1]),
+		Month:     int(raw[2]),
+		Day:       int(raw[3]),
+		Games:     int(binary.LittleEndian.Uint32(raw[4:8])),
+		Records:   int(binary.LittleEndian.Uint16(raw[8:10])),
+		GamesYear: int(binary.LittleEndian.Uint16(raw[10:12])),
+		BoardSize: int(raw[12]),
+		Solitaire: raw[13] == 1,
+		Depth:     int(raw[14]),
+	}, nil
+}
+
+// ReadGames читает партии .wtb и воспроизводит каждую, проверяя ходы
+func ReadGames(r io.Reader) (Header, []Game, error) {
+	r = bufio.NewReader(r)
+	header, err := readHeader(r)
+	if err != nil {
+		return header, nil, err
+	}
+	if header.BoardSize != 0 && header.BoardSize != Size {
+		return header, nil, fmt.Errorf("unsupported board size %d", header.BoardSize)
+	}
+
+	result := make([]Game, 0, header.Games)
+	var raw [gameSize]byte
+	for i := 0; i < header.Games; i++ {
+		if _, err := io.ReadFull(r, raw[:]); err != nil {
+			return header, nil, fmt.Errorf("game %d: %w", i+1, err)
+		}
+		g := Game{
+			Tournament:  int(binary.LittleEndian.Uint16(raw[0:2])),
+			Black:       int(binary.LittleEndian.Uint16(raw[2:4])),
+			White:       int(binary.LittleEndian.Uint16(raw[4:6])),
+			Score:       int(raw[6]),
+			Theoretical: int(raw[7]),
+		}
+		g.Moves, err = replay(raw[8:])
+		if err != nil {
+			return header, nil, fmt.Errorf("game %d: %w", i+1, err)
+		}
+		result = append(result, g)
+	}
+	return header, result, nil
+}
+
+// replay переводит ходы вида 10*строка+колонка в ходы игры, добавляя пропуски
+func replay(raw []byte) ([]game.Move, error) {
+	position := Start()
+	color := player.Green
+	result := make([]game.Move, 0, movesCount)
+	for i, code := range raw {
+		if code == 0 {
+			break
+		}
+		line, column := int(code)/10, int(code)%10
+		if line < 1 || line > 8 || column < 1 || column > 8 {
+			return nil, fmt.Errorf("move %d: bad square %d", i+1, code)
+		}
+		if position.IsTerminal() {
+			return nil, fmt.Errorf("move %d: game is over", i+1)
+		}
+		if !position.HasMoves(color) {
+			result = append(result, game.Move{Color: color, Cell: game.Pass})
+			color = color.Opponent()
+		}
+		move := game.Move{Color: color, Cell: (line-1)*8 + column - 1}
+		next, flipped := position.Apply(move)
+		if flipped.IsZero() {
+			return nil, fmt.Errorf("move %d %s: unavailable step", i+1, move)
+		}
+		position = next
+		result = append(result, move)
+		color = color.Opponent()
+	}
+	return result, nil
+}
+
+// ReadPlayers читает имена игроков .jou
+func ReadPlayers(r io.Reader) ([]string, error) {
+	return readNames(r, playerSize)
+}
+
+// ReadTournaments читает названия турниров .trn
+func ReadTournaments(r io.Reader) ([]string, error) {
+	return readNames(r, tournamentSize)
+}
+
+func readNames(r io.Reader, size int) ([]string, error) {
+	r = bufio.NewReader(r)
+	header, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, 0, header.Records)
+	raw := make([]byte, size)
+	for i := 0; i < header.Records; i++ {
+		if _, err := io.ReadFull(r, raw); err != nil {
+			return nil, fmt.Errorf("record %d: %w", i+1, err)
+		}
+		result = append(result, latin1(raw))
+	}
+	return result, nil
+}
+
+// latin1 строка до первого нулевого байта
+func latin1(raw []byte) string {
+	runes := make([]rune, 0, len(raw))
+	for _, b := range raw {
+		if b == 0 {
+			break
+		}
+		runes = append(runes, rune(b))
+	}
+	return string(runes)
+}
+
+// Database партии вместе с именами игроков и названиями турниров
+type Database struct {
+	Header      Header
+	Games       []Game
+	Players     []string
+	Tournaments []string
+}
+
+// Open читает базу из файлов; файлы игроков и турниров можно не указывать
+func Open(wtb, jou, trn string) (*Database, error) {
+	result := &Database{}
+	err := readFile(wtb, func(r io.Reader) (err error) {
+		result.Header, result.Games, err = ReadGames(r)
+		return err
+	})
+	if err == nil && jou != "" {
+		err = readFile(jou, func(r io.Reader) (err error) {
+			result.Players, err = ReadPlayers(r)
+			return err
+		})
+	}
+	if err == nil && trn != "" {
+		err = readFile(trn, func(r io.Reader) (err error) {
+			result.Tournaments, err = ReadTournaments(r)
+			return err
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func readFile(path string, read func(io.Reader) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := read(file); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Samples примеры для обучения по всем партиям базы
+func (database *Database) Samples() []game.Sample {
+	result := []game.Sample{}
+	for _, g := range database.Games {
+		result = append(result, g.Samples()...)
+	}
+	return result
+}
@@ -0,0 +1,111 @@
+package wthor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadGames(t *testing.T) {
+	data := wtb(
+		record{tournament: 3, black: 1, white: 2, score: 36, theoretical: 33, moves: []byte{56, 64, 33, 34, 43}},
+		record{tournament: 3, black: 2, white: 1, score: 30, theoretical: 32, moves: []byte{34}},
+	)
+	header, games, err := ReadGames(bytes.NewReader(data))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, Header{Century: 20, Year: 4, Month: 1, Day: 2, Games: 2, GamesYear: 2004, BoardSize: 8, Depth: 22}, header)
+	assert.Len(t, games, 2)
+	assert.Equal(t, 3, games[0].Tournament)
+	assert.Equal(t, 1, games[0].Black)
+	assert.Equal(t, 2, games[0].White)
+	assert.Equal(t, 36, games[0].Score)
+	assert.Equal(t, 33, games[0].Theoretical)
+	assert.Equal(t, "[F5 D6 C3 D3 C4]", fmt.Sprint(games[0].Moves))
+	assert.Equal(t, "[D3]", fmt.Sprint(games[1].Moves))
+
+	samples := games[0].Samples()
+	assert.Len(t, samples, 5)
+	assert.Equal(t, Start(), samples[0].Position)
+	assert.Equal(t, game.Move{Color: player.Green, Cell: 37}, samples[0].Move)
+	assert.Equal(t, 8, samples[0].Score)
+	assert.Equal(t, -8, samples[1].Score)
+}
+
+func TestReadGames_errors(t *testing.T) {
+	tests := map[string]struct {
+		data    []byte
+		wantErr string
+	}{
+		"short header": {data: []byte{20, 4}, wantErr: "header: unexpected EOF"},
+		"short game":   {data: wtb(record{moves: []byte{56}})[:40], wantErr: "game 1: unexpected EOF"},
+		"bad square":   {data: wtb(record{moves: []byte{56, 90}}), wantErr: "game 1: move 2: bad square 90"},
+		"illegal move": {data: wtb(record{moves: []byte{56, 11}}), wantErr: "game 1: move 2 A1: unavailable step"},
+	}
+	for name, tt := range tests {
+		_, _, err := ReadGames(bytes.NewReader(tt.data))
+		assert.EqualError(t, err, tt.wantErr, name)
+	}
+}
+
+func TestReadPlayers(t *testing.T) {
+	data := header(0, 2)
+	for _, name := range []string{"Tamenori Hideshi", "Brightwell Graham"} {
+		raw := make([]byte, playerSize)
+		copy(raw, name)
+		data = append(data, raw...)
+	}
+	players, err := ReadPlayers(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Tamenori Hideshi", "Brightwell Graham"}, players)
+
+	_, err = ReadPlayers(bytes.NewReader(data[:30]))
+	assert.EqualError(t, err, "record 1: unexpected EOF")
+}
+
+func Test_latin1(t *testing.T) {
+	assert.Equal(t, "Lévy", latin1([]byte{'L', 0xe9, 'v', 'y', 0, 'x'}))
+}
+
+//
+//
+// helpers and mocks
+//
+//
+
+type record struct {
+	tournament, black, white int
+	score, theoretical       byte
+	moves                    []byte
+}
+
+func header(games, records int) []byte {
+	raw := make([]byte, headerSize)
+	raw[0], raw[1], raw[2], raw[3] = 20, 4, 1, 2
+	binary.LittleEndian.PutUint32(raw[4:8], uint32(games))
+	binary.LittleEndian.PutUint16(raw[8:10], uint16(records))
+	binary.LittleEndian.PutUint16(raw[10:12], 2004)
+	raw[12] = 8
+	raw[14] = 22
+	return raw
+}
+
+func wtb(records ...record) []byte {
+	data := header(len(records), 0)
+	for _, r := range records {
+		raw := make([]byte, gameSize)
+		binary.LittleEndian.PutUint16(raw[0:2], uint16(r.tournament))
+		binary.LittleEndian.PutUint16(raw[2:4], uint16(r.black))
+		binary.LittleEndian.PutUint16(raw[4:6], uint16(r.white))
+		raw[6], raw[7] = r.score, r.theoretical
+		copy(raw[8:], r.moves)
+		data = append(data, raw...)
+	}
+	return data
+}
@@ -1,25 +1,91 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"math/rand"
+	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/slonegd-go/reversi/internal/evolution"
 	"github.com/slonegd-go/reversi/internal/game"
+	"github.com/slonegd-go/reversi/internal/player"
 	"github.com/slonegd-go/reversi/internal/player/cli"
+	"github.com/slonegd-go/reversi/internal/player/endgame"
+	"github.com/slonegd-go/reversi/internal/player/greedy"
+	"github.com/slonegd-go/reversi/internal/player/mcts"
 	"github.com/slonegd-go/reversi/internal/player/neural"
+	"github.com/slonegd-go/reversi/internal/player/random"
+	"github.com/slonegd-go/reversi/internal/player/search"
+	"github.com/slonegd-go/reversi/internal/wthor"
 )
 
 func main() {
 
 	stats := flag.Int("stats", 0, "return stats of epoch")
 	player := flag.String("player", "", "play with neural")
+	wthorFile := flag.String("wthor", "", "train neural player from -player on WTHOR games (.wtb)")
+	size := flag.Int("size", game.DefaultSize, "board size for -player, even from 4 to 10")
+	anti := flag.Bool("anti", false, "play anti-reversi: fewer discs wins")
+	randomMoves := flag.Int("random", 0, "start after this many random moves")
+	blocked := flag.String("blocked", "", "comma separated blocked cells, e.g. A1,H8")
+	setup := flag.String("setup", "", "start -player game or -perft from position, e.g. \"-------- -------- -------- ---GR--- ---RG--- -------- -------- -------- G\"")
+	base := flag.Duration("time", 0, "time per game for each player, e.g. 5m; 0 is unlimited")
+	increment := flag.Duration("inc", 0, "time added after each move with -time")
+	perMove := flag.Duration("movetime", 0, "fixed time per move instead of -time")
+	attempts := flag.Int("attempts", 0, "illegal moves allowed in a row before -penalty; 0 is unlimited")
+	penaltyName := flag.String("penalty", game.PenaltyForfeit.String(), "penalty after -attempts: forfeit, random or pass")
+	perft := flag.Int("perft", 0, "count leaf nodes up to this depth from -size start or -setup")
+	rendererName := flag.String("render", "", "board rendering for -player: plain, ansi or unicode; default ansi in a terminal")
+	opponentName := flag.String("opponent", "human", "opponent of -player: human, random, greedy, corners (greedy taking corners) search (alpha-beta) or mcts (Monte Carlo tree search), both 1s per move")
+	endgameEmpties := flag.Int("endgame", 0, "-player switches to the exact endgame solver with this many empties left, at most 20; 0 is off")
+	transcript := flag.String("transcript", "", "game from -size start or -setup to export with -svg or -gif, e.g. f5d6c3")
+	svgFile := flag.String("svg", "", "write SVG diagram of -transcript position after -ply moves")
+	gifFile := flag.String("gif", "", "write animated GIF of the whole -transcript game")
+	ply := flag.Int("ply", -1, "moves of -transcript for -svg; -1 is all")
+	highlight := flag.Bool("highlight", false, "with -render mark legal moves and the last move on the board")
 	flag.Parse()
+	variant := game.Variant{Anti: *anti, RandomMoves: *randomMoves, Seed: time.Now().UnixNano()}
+	if *blocked != "" {
+		variant.Blocked = strings.Split(*blocked, ",")
+	}
+	control := game.TimeControl{Base: *base, Increment: *increment, PerMove: *perMove}
+	penalty, err := game.ParsePenalty(*penaltyName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	policy := game.IllegalPolicy{MaxAttempts: *attempts, Penalty: penalty, Seed: time.Now().UnixNano()}
+	if err := game.CheckSize(*size); err != nil {
+		log.Fatal(err)
+	}
+	if *wthorFile != "" && *size != wthor.Size {
+		log.Fatalf("-wthor games are played on %dx%d board, got -size %d", wthor.Size, wthor.Size, *size)
+	}
+	start, _ := game.NewPositionSize(*size)
+	if *setup != "" {
+		if start, _, err = game.ParseSetup(*setup); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := variant.Check(start); err != nil {
+		log.Fatal(err)
+	}
+
+	if *perft != 0 {
+		runPerft(*size, *setup, *perft)
+		return
+	}
+
+	if *transcript != "" {
+		runExport(*size, *setup, *transcript, *ply, *svgFile, *gifFile)
+		return
+	}
 
 	if *stats != 0 {
 		epoch := *stats
@@ -53,15 +119,125 @@ func main() {
 		tmp := strings.Split(*player, "_")
 		epoch := tmp[0]
 		path := filepath.Join(".", "players", fmt.Sprintf("epoch%s", epoch))
-		n := neural.New(path, *player)
-		p := &cli.Player{}
-		currentGame := game.New(n, p, game.WithLogger(log.Printf))
-		currentGame.Start()
+		n := neural.NewSize(path, *player, *size)
+		if *wthorFile != "" {
+			database, err := wthor.Open(*wthorFile, "", "")
+			if err != nil {
+				log.Fatal(err)
+			}
+			for _, g := range database.Games {
+				n.Learn(g.Samples())
+			}
+			if err := n.Save(); err != nil {
+				log.Fatal(err)
+			}
+			log.Printf("trained on %d games", len(database.Games))
+			return
+		}
+		p, err := opponent(*opponentName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts := []game.Option{game.WithLogger(log.Printf), game.WithSize(*size), game.WithVariant(variant), game.WithTimeControl(control), game.WithIllegalPolicy(policy)}
+		if *rendererName != "" {
+			renderer, err := game.NewRenderer(*rendererName, *highlight)
+			if err != nil {
+				log.Fatal(err)
+			}
+			opts = append(opts, game.WithRenderer(renderer))
+		}
+		if *setup != "" {
+			if _, _, err := game.ParseSetup(*setup); err != nil {
+				log.Fatal(err)
+			}
+			opts = append(opts, game.WithSetup(*setup))
+		}
+		currentGame := game.New(withEndgame(n, *endgameEmpties), p, opts...)
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		currentGame.Start(ctx)
 		time.Sleep(1 * time.Second)
 		return
 	}
 
 	rand.Seed(time.Now().UnixNano())
-	evolution.Start()
+	evolution.Start(*size, variant, game.WithTimeControl(control), game.WithIllegalPolicy(policy))
 
 }
+
+// runPerft печатает число листьев для глубин от 1 до depth
+func runPerft(size int, setup string, depth int) {
+	position, _ := game.NewPositionSize(size)
+	turn := player.Green
+	if setup != "" {
+		var err error
+		if position, turn, err = game.ParseSetup(setup); err != nil {
+			log.Fatal(err)
+		}
+	}
+	for d := 1; d <= depth; d++ {
+		begin := time.Now()
+		count := game.Perft(position, turn, d)
+		fmt.Printf("perft %2d: %14d  %v\n", d, count, time.Since(begin))
+	}
+}
+
+// withEndgame игрок, который с empties пустыми клетками переходит на точное решение
+func withEndgame(p player.Player, empties int) player.Player {
+	if empties <= 0 {
+		return p
+	}
+	return endgame.Wrap(p, empties, endgame.Exact)
+}
+
+// opponent соперник нейросети по названию из флага -opponent
+func opponent(name string) (player.Player, error) {
+	switch name {
+	case "human":
+		return &cli.Player{}, nil
+	case "random":
+		return random.New(time.Now().UnixNano()), nil
+	case "greedy":
+		return greedy.New(false), nil
+	case "corners":
+		return greedy.New(true), nil
+	case "search":
+		return search.New(search.WithTime(time.Second)), nil
+	case "mcts":
+		return mcts.New(mcts.WithTime(time.Second)), nil
+	}
+	return nil, fmt.Errorf("unknown opponent %q, want human, random, greedy, corners, search or mcts", name)
+}
+
+func runExport(size int, setup, transcript string, ply int, svgFile, gifFile string) {
+	opts := []game.Option{game.WithSize(size)}
+	if setup != "" {
+		if _, _, err := game.ParseSetup(setup); err != nil {
+			log.Fatal(err)
+		}
+		opts = append(opts, game.WithSetup(setup))
+	}
+	currentGame := game.New(&cli.Player{}, &cli.Player{}, opts...)
+	if gifFile != "" {
+		if err := currentGame.Load(transcript, -1); err != nil {
+			log.Fatal(err)
+		}
+		file, err := os.Create(gifFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		if err := currentGame.WriteGIF(file, 700*time.Millisecond); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if svgFile != "" {
+		if err := currentGame.Load(transcript, ply); err != nil {
+			log.Fatal(err)
+		}
+		svg := game.SVGRenderer{Highlight: true}.Render(currentGame.View())
+		if err := ioutil.WriteFile(svgFile, []byte(svg), 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}